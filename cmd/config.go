@@ -8,17 +8,21 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/synic/glap"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/synic/buggins/internal/ipc/v1"
 	"github.com/synic/buggins/internal/mod"
 	"github.com/synic/buggins/internal/mod/featured"
 	"github.com/synic/buggins/internal/mod/inatlookup"
 	"github.com/synic/buggins/internal/mod/inatobs"
+	"github.com/synic/buggins/internal/mod/leaderboard"
+	"github.com/synic/buggins/internal/mod/milestones"
+	"github.com/synic/buggins/internal/mod/monthlyvote"
+	"github.com/synic/buggins/internal/mod/projectrole"
 	"github.com/synic/buggins/internal/mod/thisthat"
+	"github.com/synic/buggins/internal/mod/watchlist"
 	"github.com/synic/buggins/internal/store"
 )
 
@@ -32,11 +36,14 @@ var configCommandFunctions = []func() mod.ConfigCommandOptions{
 	thisthat.ConfigCommandOptions,
 	inatobs.ConfigCommandOptions,
 	inatlookup.ConfigCommandOptions,
+	milestones.ConfigCommandOptions,
+	monthlyvote.ConfigCommandOptions,
+	leaderboard.ConfigCommandOptions,
+	projectrole.ConfigCommandOptions,
+	watchlist.ConfigCommandOptions,
 }
 
 func maybeSendReload(ctx context.Context, module string) {
-	socket := fmt.Sprintf("unix://%s", ipcSocket)
-
 	if !shouldConnectIpcService {
 		return
 	}
@@ -46,10 +53,7 @@ func maybeSendReload(ctx context.Context, module string) {
 		return
 	}
 
-	conn, err := grpc.NewClient(
-		socket,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	client, conn, err := newIpcClient(ipcSocket)
 
 	if err != nil {
 		logger.Error("error connecting to ipc server", "err", err)
@@ -57,7 +61,6 @@ func maybeSendReload(ctx context.Context, module string) {
 	}
 
 	defer conn.Close()
-	client := ipc.NewIpcServiceClient(conn)
 
 	_, err = client.ReloadConfiguration(ctx, &ipc.ReloadConfigurationRequest{
 		Module: module,
@@ -357,7 +360,7 @@ func init() {
 		for _, arg := range c.Args {
 			addCmd.Arg(arg.Clone())
 			updateCmd.Arg(arg.Clone())
-			if arg.GetName() == c.KeyArg {
+			if slices.Contains(c.KeyArgs, arg.GetName()) {
 				rmCmd.Arg(arg.Clone())
 				showCmd.Arg(arg.Clone())
 			}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/synic/buggins/internal/ipc/v1"
+)
+
+func newIpcClient(socket string) (ipc.IpcServiceClient, *grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(
+		fmt.Sprintf("unix://%s", socket),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to ipc server: %w", err)
+	}
+
+	return ipc.NewIpcServiceClient(conn), conn, nil
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/ipc/v1"
+)
+
+func listScheduledJobs(m *glap.Matches) error {
+	ctx := context.Background()
+	module, _ := m.GetString("module")
+
+	client, conn, err := newIpcClient(ipcSocket)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	res, err := client.ListScheduledJobs(ctx, &ipc.ListScheduledJobsRequest{Module: module})
+
+	if err != nil {
+		return err
+	}
+
+	for _, j := range res.Jobs {
+		lastRunAt := "never"
+
+		if j.LastRunAt != nil {
+			lastRunAt = j.LastRunAt.AsTime().Format(time.RFC3339)
+		}
+
+		fmt.Printf(
+			"%s schedule=%q timezone=%s catch_up=%s next_run_at=%s last_run_at=%s last_status=%s\n",
+			j.Name,
+			j.Schedule,
+			j.Timezone,
+			j.CatchUp,
+			j.NextRunAt.AsTime().Local().Format(time.RFC3339),
+			lastRunAt,
+			j.LastStatus,
+		)
+
+		if j.LastError != "" {
+			fmt.Printf("  last_error=%s\n", j.LastError)
+		}
+	}
+
+	return nil
+}
+
+func listJobRuns(m *glap.Matches) error {
+	ctx := context.Background()
+	jobName, _ := m.GetString("job")
+	limit, _ := m.GetInt64("limit")
+
+	client, conn, err := newIpcClient(ipcSocket)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	res, err := client.ListJobRuns(ctx, &ipc.ListJobRunsRequest{JobName: jobName, Limit: limit})
+
+	if err != nil {
+		return err
+	}
+
+	for _, r := range res.Runs {
+		fmt.Printf(
+			"started_at=%s duration=%s status=%s catch_up=%t",
+			r.StartedAt.AsTime().Format(time.RFC3339),
+			r.FinishedAt.AsTime().Sub(r.StartedAt.AsTime()).Round(time.Millisecond),
+			r.Status,
+			r.CatchUp,
+		)
+
+		if r.Error != "" {
+			fmt.Printf(" error=%s", r.Error)
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func init() {
+	cmd := glap.NewCommand("jobs").
+		About("Inspect scheduled jobs").
+		SubcommandRequired(true).
+		Arg(glap.NewArg("ipc-socket").
+			Default("/tmp/buggins-ipc.sock").
+			Help("IPC socket location")).
+		Run(func(m *glap.Matches) error {
+			if v, ok := m.GetString("ipc-socket"); ok {
+				ipcSocket = v
+			}
+			return nil
+		})
+
+	listCmd := glap.NewCommand("list").
+		About("List scheduled jobs and when they will run next").
+		Arg(glap.NewArg("module").Short('m').Help("Only show jobs for MODULE")).
+		Run(func(m *glap.Matches) error {
+			err := listScheduledJobs(m)
+
+			if err != nil {
+				logger.Error("error listing scheduled jobs", "err", err)
+				return err
+			}
+
+			return nil
+		})
+
+	historyCmd := glap.NewCommand("history").
+		About("Show the most recent runs of a job").
+		Arg(glap.NewArg("job").Short('j').Required(true).Help("Job name JOB")).
+		Arg(glap.NewArg("limit").Short('n').Default("20").Help("Number of runs to show")).
+		Run(func(m *glap.Matches) error {
+			err := listJobRuns(m)
+
+			if err != nil {
+				logger.Error("error listing job runs", "err", err)
+				return err
+			}
+
+			return nil
+		})
+
+	cmd.Subcommand(listCmd).Subcommand(historyCmd)
+	RegisterCommand(cmd)
+}
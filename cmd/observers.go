@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/ipc/v1"
+)
+
+func listDisplayedObservers(m *glap.Matches) error {
+	ctx := context.Background()
+	channelID, _ := m.GetString("channel-id")
+	projectID, _ := m.GetInt64("project-id")
+	feed, _ := m.GetString("feed")
+
+	client, conn, err := newIpcClient(ipcSocket)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	res, err := client.ListDisplayedObservers(ctx, &ipc.ListDisplayedObserversRequest{
+		ChannelId: channelID,
+		ProjectId: projectID,
+		Feed:      feed,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, o := range res.Observers {
+		fmt.Printf(
+			"channel=%s project=%d feed=%s user=%d displayed_at=%s\n",
+			o.ChannelId,
+			o.ProjectId,
+			o.Feed,
+			o.UserId,
+			o.DisplayedAt.AsTime().Format(time.RFC3339),
+		)
+	}
+
+	return nil
+}
+
+func resetDisplayedObservers(m *glap.Matches) error {
+	ctx := context.Background()
+	channelID, _ := m.GetString("channel-id")
+	projectID, _ := m.GetInt64("project-id")
+	feed, _ := m.GetString("feed")
+
+	client, conn, err := newIpcClient(ipcSocket)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = client.ResetDisplayedObservers(ctx, &ipc.ResetDisplayedObserversRequest{
+		ChannelId: channelID,
+		ProjectId: projectID,
+		Feed:      feed,
+	})
+
+	return err
+}
+
+func init() {
+	cmd := glap.NewCommand("observers").
+		About("Inspect the inatobs observer rotation").
+		SubcommandRequired(true).
+		Arg(glap.NewArg("ipc-socket").
+			Default("/tmp/buggins-ipc.sock").
+			Help("IPC socket location")).
+		Run(func(m *glap.Matches) error {
+			if v, ok := m.GetString("ipc-socket"); ok {
+				ipcSocket = v
+			}
+			return nil
+		})
+
+	listCmd := glap.NewCommand("list").
+		About("List observers that have been displayed in the current rotation").
+		Arg(glap.NewArg("channel-id").Short('c').Help("Channel CHANNEL_ID")).
+		Arg(glap.NewArg("project-id").Short('p').Default("0").Help("Project PROJECT_ID")).
+		Arg(glap.NewArg("feed").Short('f').Help("Feed name FEED")).
+		Run(func(m *glap.Matches) error {
+			err := listDisplayedObservers(m)
+
+			if err != nil {
+				logger.Error("error listing displayed observers", "err", err)
+				return err
+			}
+
+			return nil
+		})
+
+	resetCmd := glap.NewCommand("reset").
+		About("Start the observer rotation over").
+		Arg(glap.NewArg("channel-id").Short('c').Required(true).Help("Channel CHANNEL_ID")).
+		Arg(glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID")).
+		Arg(glap.NewArg("feed").Short('f').Default("default").Help("Feed name FEED")).
+		Run(func(m *glap.Matches) error {
+			err := resetDisplayedObservers(m)
+
+			if err != nil {
+				logger.Error("error resetting displayed observers", "err", err)
+				return err
+			}
+
+			logger.Info("Observer rotation reset.")
+			return nil
+		})
+
+	cmd.Subcommand(listCmd).Subcommand(resetCmd)
+	RegisterCommand(cmd)
+}
@@ -12,10 +12,16 @@ import (
 
 	"github.com/synic/buggins/internal/ipc/v1"
 	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/mod/accounts"
 	"github.com/synic/buggins/internal/mod/featured"
 	"github.com/synic/buggins/internal/mod/inatlookup"
 	"github.com/synic/buggins/internal/mod/inatobs"
+	"github.com/synic/buggins/internal/mod/leaderboard"
+	"github.com/synic/buggins/internal/mod/milestones"
+	"github.com/synic/buggins/internal/mod/monthlyvote"
+	"github.com/synic/buggins/internal/mod/projectrole"
 	"github.com/synic/buggins/internal/mod/thisthat"
+	"github.com/synic/buggins/internal/mod/watchlist"
 	"github.com/synic/buggins/internal/store"
 )
 
@@ -27,10 +33,18 @@ func providers(databaseFile string) fx.Option {
 	return fx.Options(
 		fx.Provide(newLogger),
 		fx.Provide(newDatabase(databaseFile)),
+		fx.Provide(mod.SchedulerProvider),
+		fx.Provide(mod.OutboxProvider),
+		fx.Provide(accounts.Provider),
 		fx.Provide(featured.Provider),
 		fx.Provide(inatobs.Provider),
 		fx.Provide(inatlookup.Provider),
+		fx.Provide(milestones.Provider),
+		fx.Provide(monthlyvote.Provider),
+		fx.Provide(leaderboard.Provider),
+		fx.Provide(projectrole.Provider),
 		fx.Provide(thisthat.Provider),
+		fx.Provide(watchlist.Provider),
 		fx.Provide(mod.Provider),
 	)
 }
@@ -44,6 +58,7 @@ type discordSessionParams struct {
 
 	LC      fx.Lifecycle
 	Manager *mod.ModuleManager
+	Outbox  *mod.Outbox
 	DB      *store.Queries
 }
 
@@ -61,6 +76,7 @@ func newDiscordSession(
 			OnStart: func(ctx context.Context) error {
 				discord.AddHandler(func(d *discordgo.Session, r *discordgo.Ready) {
 					logger.Info("User connected to discord!", "user", r.User.Username)
+					params.Outbox.Start(discord)
 
 					for _, module := range params.Manager.Modules() {
 						module.Start(ctx, discord, params.DB)
@@ -98,11 +114,12 @@ func newDatabase(fileLocation string) func() (*store.Queries, error) {
 type ipcServiceParams struct {
 	fx.In
 
-	LC      fx.Lifecycle
-	Manager *mod.ModuleManager
-	DB      *store.Queries
-	Discord *discordgo.Session
-	Logger  *slog.Logger
+	LC        fx.Lifecycle
+	Manager   *mod.ModuleManager
+	Scheduler *mod.Scheduler
+	DB        *store.Queries
+	Discord   *discordgo.Session
+	Logger    *slog.Logger
 }
 
 func startIpcService(bind string) func(
@@ -110,7 +127,13 @@ func startIpcService(bind string) func(
 ) (*ipc.Service, error) {
 	return func(params ipcServiceParams) (*ipc.Service, error) {
 		var opts []grpc.ServerOption
-		service, err := ipc.New(params.Discord, params.DB, params.Manager, logger)
+		service, err := ipc.New(
+			params.Discord,
+			params.DB,
+			params.Manager,
+			params.Scheduler,
+			logger,
+		)
 		if err != nil {
 			return nil, err
 		}
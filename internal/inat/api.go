@@ -4,101 +4,228 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"maps"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 )
 
+var (
+	apiURL = "https://api.inaturalist.org/v1"
+)
+
 type Api struct {
+	baseURL          string
+	locale           string
+	preferredPlaceID int64
 }
 
 func New() Api {
-	return Api{}
+	return Api{baseURL: apiURL}
 }
 
-func (a Api) Search(sources []string, q string) (SearchResult, error) {
-	var sr SearchResult
+// WithBaseURL returns a copy of the api that sends its requests to another
+// server, like a test server
+func (a Api) WithBaseURL(baseURL string) Api {
+	a.baseURL = strings.TrimSuffix(baseURL, "/")
+	return a
+}
+
+// Localized returns a copy of the api that asks for common names in a locale,
+// preferring the names used in a place. Either can be left empty.
+func (a Api) Localized(locale string, preferredPlaceID int64) Api {
+	a.locale = locale
+	a.preferredPlaceID = preferredPlaceID
+	return a
+}
+
+func (a Api) get(path string, params url.Values, v any) error {
+	baseURL := a.baseURL
+
+	if baseURL == "" {
+		baseURL = apiURL
+	}
+
+	u := fmt.Sprintf("%s%s", baseURL, path)
+
+	if a.locale != "" || a.preferredPlaceID != 0 {
+		params = maps.Clone(params)
+
+		if params == nil {
+			params = url.Values{}
+		}
 
-	res, err := http.Get(
-		fmt.Sprintf("https://api.inaturalist.org/v1/search?q=%s&sources=%s",
-			url.QueryEscape(q), strings.Join(sources, ",")),
-	)
+		if a.locale != "" {
+			params.Set("locale", a.locale)
+		}
+
+		if a.preferredPlaceID != 0 {
+			params.Set("preferred_place_id", strconv.FormatInt(a.preferredPlaceID, 10))
+		}
+	}
+
+	if len(params) > 0 {
+		u = fmt.Sprintf("%s?%s", u, params.Encode())
+	}
+
+	res, err := http.Get(u)
 
 	if err != nil {
-		return sr, fmt.Errorf("http error: %w", err)
+		return fmt.Errorf("http error: %w", err)
 	}
 
 	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %s", path, res.Status)
+	}
+
 	body, err := io.ReadAll(res.Body)
 
 	if err != nil {
-		return sr, fmt.Errorf("error parsing body: %w", err)
+		return fmt.Errorf("error parsing body: %w", err)
 	}
 
-	err = json.Unmarshal(body, &sr)
+	err = json.Unmarshal(body, v)
 
 	if err != nil {
-		return sr, fmt.Errorf("error parsing json: %w", err)
+		return fmt.Errorf("error parsing json: %w", err)
 	}
 
-	return sr, nil
-}
-
-func (a Api) FetchRecentProjectObservations(
-	projectID int64,
-	pages int,
-	pageSize int,
-) ([]Observation, error) {
-	var (
-		observations []Observation
-		currentPage  = 0
-	)
-
-	for currentPage < pages {
-		log.Printf("Connecting to inat for page %d", currentPage)
-		res, err := http.Get(
-			fmt.Sprintf(
-				"https://inaturalist.org/observations/project/%d.json?order_by=id&order=desc&per_page=%d",
-				projectID,
-				pageSize,
-			),
-		)
-
-		if err != nil {
-			return observations, fmt.Errorf("http error: %w", err)
-		}
+	return nil
+}
 
-		defer res.Body.Close()
-		body, err := io.ReadAll(res.Body)
+func (a Api) Search(sources []string, q string) (SearchResult, error) {
+	var sr SearchResult
 
-		if err != nil {
-			return observations, fmt.Errorf("error parsing body: %w", err)
-		}
+	err := a.get("/search", url.Values{
+		"q":       {q},
+		"sources": {strings.Join(sources, ",")},
+	}, &sr)
 
-		var items []Observation
-		err = json.Unmarshal(body, &items)
+	return sr, err
+}
 
-		if err != nil {
-			return observations, fmt.Errorf("error parsing json: %w", err)
-		}
+// FetchObservations queries the `/observations` endpoint. Paging is left to
+// the caller, usually by way of the `id_above`/`id_below` parameters.
+func (a Api) FetchObservations(params url.Values) (ObservationResult, error) {
+	var r ObservationResult
+	err := a.get("/observations", params, &r)
+	return r, err
+}
 
-		for _, item := range items {
-			if len(item.Photos) > 0 {
-				if item.Photos[0].MediumURL != "" {
-					observations = append(observations, item)
-				}
-			}
-		}
+// FetchSpeciesCounts queries the `/observations/species_counts` endpoint. The
+// number of species is in `TotalResults`.
+func (a Api) FetchSpeciesCounts(params url.Values) (SpeciesCountResult, error) {
+	var r SpeciesCountResult
+	err := a.get("/observations/species_counts", params, &r)
+	return r, err
+}
 
-		if len(items) < pageSize {
-			break
-		}
+// FetchObservers queries the `/observations/observers` endpoint. The number
+// of observers is in `TotalResults`.
+func (a Api) FetchObservers(params url.Values) (ObserverResult, error) {
+	var r ObserverResult
+	err := a.get("/observations/observers", params, &r)
+	return r, err
+}
+
+// FetchIdentifiers queries the `/observations/identifiers` endpoint
+func (a Api) FetchIdentifiers(params url.Values) (IdentifierResult, error) {
+	var r IdentifierResult
+	err := a.get("/observations/identifiers", params, &r)
+	return r, err
+}
+
+func (a Api) FetchProject(id int64) (Project, error) {
+	var r ProjectResult
+
+	if err := a.get(fmt.Sprintf("/projects/%d", id), nil, &r); err != nil {
+		return Project{}, err
+	}
+
+	if len(r.Results) <= 0 {
+		return Project{}, fmt.Errorf("project %d not found", id)
+	}
+
+	return r.Results[0], nil
+}
+
+// FetchProjectBySlug fetches a project by its slug, which is the last part of
+// its url
+func (a Api) FetchProjectBySlug(slug string) (Project, error) {
+	var r ProjectResult
+
+	if err := a.get(fmt.Sprintf("/projects/%s", url.PathEscape(slug)), nil, &r); err != nil {
+		return Project{}, err
+	}
+
+	if len(r.Results) <= 0 {
+		return Project{}, fmt.Errorf("project %s not found", slug)
+	}
+
+	return r.Results[0], nil
+}
 
-		currentPage += 1
+// FetchProjectMembers queries the `/projects/{id}/members` endpoint. It is
+// paged with the `page` parameter.
+func (a Api) FetchProjectMembers(id int64, params url.Values) (ProjectMemberResult, error) {
+	var r ProjectMemberResult
+	err := a.get(fmt.Sprintf("/projects/%d/members", id), params, &r)
+	return r, err
+}
+
+// AutocompleteTaxa queries the `/taxa/autocomplete` endpoint, which is meant
+// for searching as the user types
+func (a Api) AutocompleteTaxa(q string) (TaxonResult, error) {
+	var r TaxonResult
+	err := a.get("/taxa/autocomplete", url.Values{"q": {q}}, &r)
+	return r, err
+}
+
+func (a Api) FetchTaxon(id int64) (Taxon, error) {
+	var r TaxonResult
+
+	if err := a.get(fmt.Sprintf("/taxa/%d", id), nil, &r); err != nil {
+		return Taxon{}, err
 	}
 
-	log.Printf("%d recent observations to examine", len(observations))
+	if len(r.Results) <= 0 {
+		return Taxon{}, fmt.Errorf("taxon %d not found", id)
+	}
+
+	return r.Results[0], nil
+}
+
+// AutocompletePlaces queries the `/places/autocomplete` endpoint
+func (a Api) AutocompletePlaces(q string) (PlaceResult, error) {
+	var r PlaceResult
+	err := a.get("/places/autocomplete", url.Values{"q": {q}}, &r)
+	return r, err
+}
+
+// FetchSimilarSpecies queries the `/identifications/similar_species`
+// endpoint, which lists the taxa a taxon is most often mistaken for
+func (a Api) FetchSimilarSpecies(taxonID int64) (SimilarSpeciesResult, error) {
+	var r SimilarSpeciesResult
+	err := a.get("/identifications/similar_species", url.Values{
+		"taxon_id": {strconv.FormatInt(taxonID, 10)},
+	}, &r)
+	return r, err
+}
+
+// FetchUser fetches a user by id or login
+func (a Api) FetchUser(idOrLogin string) (User, error) {
+	var r UserResult
+
+	if err := a.get(fmt.Sprintf("/users/%s", url.PathEscape(idOrLogin)), nil, &r); err != nil {
+		return User{}, err
+	}
+
+	if len(r.Results) <= 0 {
+		return User{}, fmt.Errorf("user %s not found", idOrLogin)
+	}
 
-	return observations, nil
+	return r.Results[0], nil
 }
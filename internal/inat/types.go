@@ -1,36 +1,102 @@
 package inat
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// PhotoLicenses are the creative commons licenses photos are shared under.
+// Photos without a license have all rights reserved.
+var PhotoLicenses = []string{
+	"cc0", "cc-by", "cc-by-nc", "cc-by-sa", "cc-by-nd", "cc-by-nc-sa", "cc-by-nc-nd",
+}
+
 // Observations
 type Photo struct {
-	MediumURL string `json:"medium_url"`
+	URL         string `json:"url"`
+	MediumURL   string `json:"medium_url"`
+	LicenseCode string `json:"license_code"`
+	Attribution string `json:"attribution"`
+	ID          int64  `json:"id"`
 }
 
-type observationTaxonName struct {
-	Name string `json:"name"`
+// Medium returns the medium sized version of the photo. Observation photos
+// from the v1 api only include the square thumbnail url, so derive it.
+func (p Photo) Medium() string {
+	if p.MediumURL != "" {
+		return p.MediumURL
+	}
+
+	return strings.Replace(p.URL, "/square.", "/medium.", 1)
+}
+
+// Licensed reports whether the photo may be posted under one of the given
+// licenses, or any of `PhotoLicenses` if none are given. Photos with all
+// rights reserved never may.
+func (p Photo) Licensed(licenses ...string) bool {
+	if len(licenses) <= 0 {
+		licenses = PhotoLicenses
+	}
+
+	code := strings.ToLower(p.LicenseCode)
+	return code != "" && slices.Contains(licenses, code)
 }
 
 type observationTaxon struct {
-	Name        string               `json:"name"`
-	CommonName  observationTaxonName `json:"common_name"`
-	DefaultName observationTaxonName `json:"default_name"`
+	Name                string  `json:"name"`
+	PreferredCommonName string  `json:"preferred_common_name"`
+	Rank                string  `json:"rank"`
+	IconicTaxonName     string  `json:"iconic_taxon_name"`
+	AncestorIDs         []int64 `json:"ancestor_ids"`
+	ID                  int64   `json:"id"`
+	ObservationsCount   int64   `json:"observations_count"`
 }
 
 type observationUser struct {
 	Username    string `json:"login"`
-	Name        string `json:"string"`
-	UserIconURL string `json:"user_icon_url"`
+	Name        string `json:"name"`
+	UserIconURL string `json:"icon_url"`
 	ID          int64  `json:"id"`
 }
 
 type Observation struct {
-	Taxon      observationTaxon `json:"taxon"`
-	Species    string           `json:"species_guess"`
-	ObservedOn string           `json:"observed_on"`
-	Username   string           `json:"user_login"`
-	User       observationUser  `json:"user"`
-	Photos     []Photo          `json:"photos"`
-	ID         int64            `json:"id"`
-	UserID     int64            `json:"user_id"`
+	Taxon                observationTaxon `json:"taxon"`
+	Species              string           `json:"species_guess"`
+	ObservedOn           string           `json:"observed_on"`
+	PlaceGuess           string           `json:"place_guess"`
+	QualityGrade         string           `json:"quality_grade"`
+	User                 observationUser  `json:"user"`
+	Photos               []Photo          `json:"photos"`
+	PlaceIDs             []int64          `json:"place_ids"`
+	ID                   int64            `json:"id"`
+	FavesCount           int64            `json:"faves_count"`
+	IdentificationsCount int64            `json:"identifications_count"`
+}
+
+// DisplayName returns the observer's name, or their login if they haven't
+// set one
+func (u observationUser) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+
+	return u.Username
+}
+
+type ObservationResult struct {
+	Results      []Observation `json:"results"`
+	TotalResults int           `json:"total_results"`
+	Page         int           `json:"page"`
+	PerPage      int           `json:"per_page"`
+}
+
+// LicensedPhotos returns the observation's photos that may be posted, see
+// `Photo.Licensed`
+func (o Observation) LicensedPhotos(licenses ...string) []Photo {
+	return slices.DeleteFunc(slices.Clone(o.Photos), func(p Photo) bool {
+		return !p.Licensed(licenses...)
+	})
 }
 
 func (o Observation) TaxonNames() (string, string) {
@@ -41,10 +107,8 @@ func (o Observation) TaxonNames() (string, string) {
 	if taxon.Name != "" {
 		taxonName = taxon.Name
 
-		if taxon.CommonName.Name != "" {
-			commonName = taxon.CommonName.Name
-		} else if taxon.DefaultName.Name != "" {
-			commonName = taxon.DefaultName.Name
+		if taxon.PreferredCommonName != "" {
+			commonName = taxon.PreferredCommonName
 		} else if o.Species != "" {
 			commonName = o.Species
 		}
@@ -53,6 +117,74 @@ func (o Observation) TaxonNames() (string, string) {
 	return taxonName, commonName
 }
 
+// Stats
+type SpeciesCount struct {
+	Taxon observationTaxon `json:"taxon"`
+	Count int64            `json:"count"`
+}
+
+type SpeciesCountResult struct {
+	Results      []SpeciesCount `json:"results"`
+	TotalResults int64          `json:"total_results"`
+}
+
+type ObserverCount struct {
+	User             observationUser `json:"user"`
+	ObservationCount int64           `json:"observation_count"`
+	SpeciesCount     int64           `json:"species_count"`
+}
+
+type ObserverResult struct {
+	Results      []ObserverCount `json:"results"`
+	TotalResults int64           `json:"total_results"`
+}
+
+type IdentifierCount struct {
+	User  observationUser `json:"user"`
+	Count int64           `json:"count"`
+}
+
+type IdentifierResult struct {
+	Results      []IdentifierCount `json:"results"`
+	TotalResults int64             `json:"total_results"`
+}
+
+// Projects
+type Project struct {
+	Title       string `json:"title"`
+	Slug        string `json:"slug"`
+	IconURL     string `json:"icon"`
+	Description string `json:"description"`
+	ID          int64  `json:"id"`
+}
+
+type ProjectResult struct {
+	Results []Project `json:"results"`
+}
+
+type ProjectMember struct {
+	User observationUser `json:"user"`
+	Role string          `json:"role"`
+}
+
+type ProjectMemberResult struct {
+	Results      []ProjectMember `json:"results"`
+	TotalResults int64           `json:"total_results"`
+	Page         int             `json:"page"`
+	PerPage      int             `json:"per_page"`
+}
+
+// Places
+type Place struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	ID          int64  `json:"id"`
+}
+
+type PlaceResult struct {
+	Results []Place `json:"results"`
+}
+
 // taxa
 type Taxa struct {
 	Rank                string `json:"rank"`
@@ -61,6 +193,47 @@ type Taxa struct {
 	ObservationCount    int64  `json:"observations_count"`
 }
 
+// Taxon is a taxon from the `/taxa` endpoint
+type Taxon struct {
+	Name string `json:"name"`
+	Taxa
+	WikipediaSummary string `json:"wikipedia_summary"`
+	// ConservationStatus and Ancestors are only included when fetching a
+	// single taxon
+	ConservationStatus *ConservationStatus `json:"conservation_status"`
+	Ancestors          []Taxon             `json:"ancestors"`
+	AncestorIDs        []int64             `json:"ancestor_ids"`
+	ID                 int64               `json:"id"`
+}
+
+type ConservationStatus struct {
+	StatusName string `json:"status_name"`
+	Status     string `json:"status"`
+	Authority  string `json:"authority"`
+}
+
+type SimilarSpecies struct {
+	Taxon Taxon `json:"taxon"`
+	Count int64 `json:"count"`
+}
+
+type SimilarSpeciesResult struct {
+	Results []SimilarSpecies `json:"results"`
+}
+
+// DisplayName returns the taxon's name, with its common name if it has one
+func (t Taxon) DisplayName() string {
+	if t.PreferredCommonName != "" {
+		return fmt.Sprintf("%s (%s)", t.Name, t.PreferredCommonName)
+	}
+
+	return t.Name
+}
+
+type TaxonResult struct {
+	Results []Taxon `json:"results"`
+}
+
 // Search Results
 type SearchRecord struct {
 	Name         string `json:"name"`
@@ -69,10 +242,18 @@ type SearchRecord struct {
 	ID int64 `json:"id"`
 }
 
+// Taxon returns the record as a taxon
+func (r SearchRecord) Taxon() Taxon {
+	taxa := r.Taxa
+	taxa.DefaultPhoto = r.DefaultPhoto
+	return Taxon{Name: r.Name, Taxa: taxa, ID: r.ID}
+}
+
 type SearchResultItem struct {
 	Type    string       `json:"type"`
 	Matches []string     `json:"matches"`
 	Record  SearchRecord `json:"record"`
+	Score   float64      `json:"score"`
 }
 
 type SearchResult struct {
@@ -81,3 +262,28 @@ type SearchResult struct {
 	Page         int                `json:"page"`
 	PerPage      int                `json:"per_page"`
 }
+
+// Users
+type User struct {
+	Login                string `json:"login"`
+	Name                 string `json:"name"`
+	IconURL              string `json:"icon_url"`
+	Description          string `json:"description"`
+	ID                   int64  `json:"id"`
+	ObservationsCount    int64  `json:"observations_count"`
+	IdentificationsCount int64  `json:"identifications_count"`
+	SpeciesCount         int64  `json:"species_count"`
+}
+
+// DisplayName returns the user's name, or their login if they haven't set one
+func (u User) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+
+	return u.Login
+}
+
+type UserResult struct {
+	Results []User `json:"results"`
+}
@@ -5,28 +5,48 @@ import (
 	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/synic/buggins/internal/mod"
 	"github.com/synic/buggins/internal/store"
 )
 
+type displayedObserversResetter interface {
+	ResetDisplayedObservers(
+		ctx context.Context,
+		channelID string,
+		projectID int64,
+		feed string,
+	) error
+}
+
 type Service struct {
 	UnimplementedIpcServiceServer
-	discord *discordgo.Session
-	manager *mod.ModuleManager
-	db      *store.Queries
-	logger  *slog.Logger
+	discord   *discordgo.Session
+	manager   *mod.ModuleManager
+	scheduler *mod.Scheduler
+	db        *store.Queries
+	logger    *slog.Logger
 }
 
 func New(
 	discord *discordgo.Session,
 	db *store.Queries,
 	manager *mod.ModuleManager,
+	scheduler *mod.Scheduler,
 	logger *slog.Logger,
 
 ) (*Service, error) {
-	return &Service{discord: discord, manager: manager, db: db, logger: logger}, nil
+	return &Service{
+		discord:   discord,
+		manager:   manager,
+		scheduler: scheduler,
+		db:        db,
+		logger:    logger,
+	}, nil
 }
 
 func (s *Service) ReloadConfiguration(
@@ -41,3 +61,150 @@ func (s *Service) ReloadConfiguration(
 	}
 	return &emptypb.Empty{}, nil
 }
+
+func (s *Service) ListDisplayedObservers(
+	ctx context.Context,
+	request *ListDisplayedObserversRequest,
+) (*ListDisplayedObserversResponse, error) {
+	rows, err := s.db.FindDisplayedObservers(ctx, store.FindDisplayedObserversParams{
+		ChannelID: request.ChannelId,
+		ProjectID: request.ProjectId,
+		Feed:      request.Feed,
+	})
+
+	if err != nil {
+		s.logger.Error("error fetching displayed observers", "err", err)
+		return nil, status.Error(codes.Internal, "could not fetch displayed observers")
+	}
+
+	observers := make([]*DisplayedObserver, 0, len(rows))
+
+	for _, row := range rows {
+		observers = append(observers, &DisplayedObserver{
+			ChannelId:   row.ChannelID,
+			ProjectId:   row.ProjectID,
+			Feed:        row.Feed,
+			UserId:      row.UserID,
+			DisplayedAt: timestamppb.New(row.CreatedAt),
+		})
+	}
+
+	return &ListDisplayedObserversResponse{Observers: observers}, nil
+}
+
+func (s *Service) ResetDisplayedObservers(
+	ctx context.Context,
+	request *ResetDisplayedObserversRequest,
+) (*emptypb.Empty, error) {
+	if request.ChannelId == "" || request.ProjectId == 0 || request.Feed == "" {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"channel_id, project_id and feed are required",
+		)
+	}
+
+	for _, m := range s.manager.Modules() {
+		if r, ok := m.(displayedObserversResetter); ok {
+			s.logger.Info(
+				"Resetting displayed observers",
+				"module",
+				m.Name(),
+				"channel",
+				request.ChannelId,
+				"project",
+				request.ProjectId,
+				"feed",
+				request.Feed,
+			)
+
+			err := r.ResetDisplayedObservers(
+				ctx,
+				request.ChannelId,
+				request.ProjectId,
+				request.Feed,
+			)
+
+			if err != nil {
+				s.logger.Error("error resetting displayed observers", "err", err)
+				return nil, status.Error(codes.Internal, "could not reset displayed observers")
+			}
+		}
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) ListScheduledJobs(
+	ctx context.Context,
+	request *ListScheduledJobsRequest,
+) (*ListScheduledJobsResponse, error) {
+	upcoming, err := s.scheduler.Upcoming(ctx)
+
+	if err != nil {
+		s.logger.Error("error fetching scheduled jobs", "err", err)
+		return nil, status.Error(codes.Internal, "could not fetch scheduled jobs")
+	}
+
+	jobs := make([]*ScheduledJob, 0, len(upcoming))
+
+	for _, u := range upcoming {
+		if request.Module != "" && u.Job.Module != request.Module {
+			continue
+		}
+
+		job := &ScheduledJob{
+			Name:       u.Job.Name,
+			Module:     u.Job.Module,
+			Schedule:   u.Job.Schedule,
+			Timezone:   u.Job.Timezone,
+			CatchUp:    u.Job.CatchUp,
+			NextRunAt:  timestamppb.New(u.NextRunAt),
+			LastStatus: u.Job.LastStatus,
+			LastError:  u.Job.LastError,
+		}
+
+		if u.Job.LastRunAt.Valid {
+			job.LastRunAt = timestamppb.New(u.Job.LastRunAt.Time)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return &ListScheduledJobsResponse{Jobs: jobs}, nil
+}
+
+func (s *Service) ListJobRuns(
+	ctx context.Context,
+	request *ListJobRunsRequest,
+) (*ListJobRunsResponse, error) {
+	limit := request.Limit
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := s.db.FindJobRuns(ctx, store.FindJobRunsParams{
+		JobName: request.JobName,
+		Limit:   limit,
+	})
+
+	if err != nil {
+		s.logger.Error("error fetching job runs", "err", err)
+		return nil, status.Error(codes.Internal, "could not fetch job runs")
+	}
+
+	runs := make([]*JobRun, 0, len(rows))
+
+	for _, row := range rows {
+		runs = append(runs, &JobRun{
+			JobName:    row.JobName,
+			StartedAt:  timestamppb.New(row.StartedAt),
+			FinishedAt: timestamppb.New(row.FinishedAt),
+			Status:     row.Status,
+			Error:      row.Error,
+			CatchUp:    row.CatchUp,
+		})
+	}
+
+	return &ListJobRunsResponse{Runs: runs}, nil
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.5.1
+// - protoc-gen-go-grpc v1.6.1
 // - protoc             v5.28.2
 // source: ipc.proto
 
@@ -20,7 +20,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	IpcService_ReloadConfiguration_FullMethodName = "/ipc.v1.IpcService/ReloadConfiguration"
+	IpcService_ReloadConfiguration_FullMethodName     = "/ipc.v1.IpcService/ReloadConfiguration"
+	IpcService_ListDisplayedObservers_FullMethodName  = "/ipc.v1.IpcService/ListDisplayedObservers"
+	IpcService_ResetDisplayedObservers_FullMethodName = "/ipc.v1.IpcService/ResetDisplayedObservers"
+	IpcService_ListScheduledJobs_FullMethodName       = "/ipc.v1.IpcService/ListScheduledJobs"
+	IpcService_ListJobRuns_FullMethodName             = "/ipc.v1.IpcService/ListJobRuns"
 )
 
 // IpcServiceClient is the client API for IpcService service.
@@ -28,6 +32,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type IpcServiceClient interface {
 	ReloadConfiguration(ctx context.Context, in *ReloadConfigurationRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListDisplayedObservers(ctx context.Context, in *ListDisplayedObserversRequest, opts ...grpc.CallOption) (*ListDisplayedObserversResponse, error)
+	ResetDisplayedObservers(ctx context.Context, in *ResetDisplayedObserversRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListScheduledJobs(ctx context.Context, in *ListScheduledJobsRequest, opts ...grpc.CallOption) (*ListScheduledJobsResponse, error)
+	ListJobRuns(ctx context.Context, in *ListJobRunsRequest, opts ...grpc.CallOption) (*ListJobRunsResponse, error)
 }
 
 type ipcServiceClient struct {
@@ -48,11 +56,55 @@ func (c *ipcServiceClient) ReloadConfiguration(ctx context.Context, in *ReloadCo
 	return out, nil
 }
 
+func (c *ipcServiceClient) ListDisplayedObservers(ctx context.Context, in *ListDisplayedObserversRequest, opts ...grpc.CallOption) (*ListDisplayedObserversResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDisplayedObserversResponse)
+	err := c.cc.Invoke(ctx, IpcService_ListDisplayedObservers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipcServiceClient) ResetDisplayedObservers(ctx context.Context, in *ResetDisplayedObserversRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, IpcService_ResetDisplayedObservers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipcServiceClient) ListScheduledJobs(ctx context.Context, in *ListScheduledJobsRequest, opts ...grpc.CallOption) (*ListScheduledJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledJobsResponse)
+	err := c.cc.Invoke(ctx, IpcService_ListScheduledJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipcServiceClient) ListJobRuns(ctx context.Context, in *ListJobRunsRequest, opts ...grpc.CallOption) (*ListJobRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobRunsResponse)
+	err := c.cc.Invoke(ctx, IpcService_ListJobRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IpcServiceServer is the server API for IpcService service.
 // All implementations must embed UnimplementedIpcServiceServer
 // for forward compatibility.
 type IpcServiceServer interface {
 	ReloadConfiguration(context.Context, *ReloadConfigurationRequest) (*emptypb.Empty, error)
+	ListDisplayedObservers(context.Context, *ListDisplayedObserversRequest) (*ListDisplayedObserversResponse, error)
+	ResetDisplayedObservers(context.Context, *ResetDisplayedObserversRequest) (*emptypb.Empty, error)
+	ListScheduledJobs(context.Context, *ListScheduledJobsRequest) (*ListScheduledJobsResponse, error)
+	ListJobRuns(context.Context, *ListJobRunsRequest) (*ListJobRunsResponse, error)
 	mustEmbedUnimplementedIpcServiceServer()
 }
 
@@ -64,7 +116,19 @@ type IpcServiceServer interface {
 type UnimplementedIpcServiceServer struct{}
 
 func (UnimplementedIpcServiceServer) ReloadConfiguration(context.Context, *ReloadConfigurationRequest) (*emptypb.Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfiguration not implemented")
+	return nil, status.Error(codes.Unimplemented, "method ReloadConfiguration not implemented")
+}
+func (UnimplementedIpcServiceServer) ListDisplayedObservers(context.Context, *ListDisplayedObserversRequest) (*ListDisplayedObserversResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDisplayedObservers not implemented")
+}
+func (UnimplementedIpcServiceServer) ResetDisplayedObservers(context.Context, *ResetDisplayedObserversRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResetDisplayedObservers not implemented")
+}
+func (UnimplementedIpcServiceServer) ListScheduledJobs(context.Context, *ListScheduledJobsRequest) (*ListScheduledJobsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListScheduledJobs not implemented")
+}
+func (UnimplementedIpcServiceServer) ListJobRuns(context.Context, *ListJobRunsRequest) (*ListJobRunsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListJobRuns not implemented")
 }
 func (UnimplementedIpcServiceServer) mustEmbedUnimplementedIpcServiceServer() {}
 func (UnimplementedIpcServiceServer) testEmbeddedByValue()                    {}
@@ -77,7 +141,7 @@ type UnsafeIpcServiceServer interface {
 }
 
 func RegisterIpcServiceServer(s grpc.ServiceRegistrar, srv IpcServiceServer) {
-	// If the following call pancis, it indicates UnimplementedIpcServiceServer was
+	// If the following call panics, it indicates UnimplementedIpcServiceServer was
 	// embedded by pointer and is nil.  This will cause panics if an
 	// unimplemented method is ever invoked, so we test this at initialization
 	// time to prevent it from happening at runtime later due to I/O.
@@ -105,6 +169,78 @@ func _IpcService_ReloadConfiguration_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IpcService_ListDisplayedObservers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDisplayedObserversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpcServiceServer).ListDisplayedObservers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IpcService_ListDisplayedObservers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpcServiceServer).ListDisplayedObservers(ctx, req.(*ListDisplayedObserversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IpcService_ResetDisplayedObservers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetDisplayedObserversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpcServiceServer).ResetDisplayedObservers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IpcService_ResetDisplayedObservers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpcServiceServer).ResetDisplayedObservers(ctx, req.(*ResetDisplayedObserversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IpcService_ListScheduledJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpcServiceServer).ListScheduledJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IpcService_ListScheduledJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpcServiceServer).ListScheduledJobs(ctx, req.(*ListScheduledJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IpcService_ListJobRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpcServiceServer).ListJobRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IpcService_ListJobRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpcServiceServer).ListJobRuns(ctx, req.(*ListJobRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // IpcService_ServiceDesc is the grpc.ServiceDesc for IpcService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,6 +252,22 @@ var IpcService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReloadConfiguration",
 			Handler:    _IpcService_ReloadConfiguration_Handler,
 		},
+		{
+			MethodName: "ListDisplayedObservers",
+			Handler:    _IpcService_ListDisplayedObservers_Handler,
+		},
+		{
+			MethodName: "ResetDisplayedObservers",
+			Handler:    _IpcService_ResetDisplayedObservers_Handler,
+		},
+		{
+			MethodName: "ListScheduledJobs",
+			Handler:    _IpcService_ListScheduledJobs_Handler,
+		},
+		{
+			MethodName: "ListJobRuns",
+			Handler:    _IpcService_ListJobRuns_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ipc.proto",
@@ -0,0 +1,315 @@
+package accounts
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName = "accounts"
+	// verificationTTL is how long a member has to put their code in their bio
+	verificationTTL = time.Hour
+
+	errNoVerification = errors.New("no pending account verification")
+	errCodeNotFound   = errors.New("verification code not found in bio")
+	errFetchUser      = errors.New("error fetching inat user")
+)
+
+// Module links discord members to their iNaturalist accounts. A member proves
+// they own an account by adding a one-time code to its profile bio.
+type Module struct {
+	api                     inat.Api
+	db                      *store.Queries
+	logger                  *slog.Logger
+	slashCommandsRegistered bool
+}
+
+func New(db *store.Queries, logger *slog.Logger) (*Module, error) {
+	return &Module{api: inat.New(), db: db, logger: logger}, nil
+}
+
+func Provider(db *store.Queries, logger *slog.Logger) (mod.ModuleProviderResult, error) {
+	module, err := New(db, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	if !m.slashCommandsRegistered {
+		m.registerHandlers(discord)
+	}
+
+	m.logger.Info("started module")
+	return nil
+}
+
+// ReloadConfig does nothing, linking accounts doesn't need any configuration
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	return nil
+}
+
+func (m *Module) registerHandlers(discord *discordgo.Session) {
+	m.slashCommandsRegistered = true
+	command := discordgo.ApplicationCommand{
+		Name:        "inat",
+		Description: "Manage your linked iNaturalist account",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "link",
+				Description: "Link your iNaturalist account",
+				Options: []*discordgo.ApplicationCommandOption{{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "login",
+					Description: "Your iNaturalist username",
+					Required:    true,
+				}},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "verify",
+				Description: "Finish linking your iNaturalist account",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "unlink",
+				Description: "Unlink your iNaturalist account",
+			},
+		},
+	}
+
+	_, err := discord.ApplicationCommandCreate(discord.State.Application.ID, "", &command)
+
+	if err != nil {
+		m.logger.Warn("error creating /inat command", "err", err)
+	}
+
+	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
+		switch i.Type {
+		case discordgo.InteractionMessageComponent:
+			module, action, _, ok := mod.ParseComponentID(i.MessageComponentData().CustomID)
+
+			if ok && module == moduleName && action == "verify" {
+				m.handleVerify(d, i)
+			}
+		case discordgo.InteractionApplicationCommand:
+			data := i.ApplicationCommandData()
+
+			if data.Name != "inat" || len(data.Options) <= 0 {
+				return
+			}
+
+			switch data.Options[0].Name {
+			case "link":
+				m.handleLink(d, i, data.Options[0])
+			case "verify":
+				m.handleVerify(d, i)
+			case "unlink":
+				m.handleUnlink(d, i)
+			}
+		}
+	})
+
+	m.logger.Info(" -> accounts slash commands registered")
+}
+
+// handleLink starts linking an account. The member is given a code to add to
+// their iNaturalist bio, and a button to press once they have.
+func (m *Module) handleLink(
+	d *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	option *discordgo.ApplicationCommandInteractionDataOption,
+) {
+	user := mod.InteractionUser(i)
+	login := strings.TrimPrefix(strings.TrimSpace(option.Options[0].StringValue()), "@")
+	code, err := m.startLink(context.Background(), user.ID, login)
+
+	if errors.Is(err, errFetchUser) {
+		m.logger.Warn("error fetching inat user", "login", login, "err", err)
+		mod.RespondEphemeral(d, i, fmt.Sprintf("Sorry, I couldn't find the iNaturalist user `%s`.", login))
+		return
+	}
+
+	if err != nil {
+		m.logger.Error("error saving account verification", "user", user.ID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf(
+				"To prove `%s` is yours, add `%s` anywhere in your "+
+					"[iNaturalist bio](https://www.inaturalist.org/users/edit), then press "+
+					"**Verify** or use `/inat verify` within the hour. You can remove the code "+
+					"once you're linked.",
+				login,
+				code,
+			),
+			Flags: discordgo.MessageFlagsEphemeral,
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "Verify",
+						Style:    discordgo.PrimaryButton,
+						CustomID: mod.ComponentID(moduleName, "verify"),
+					},
+				}},
+			},
+		},
+	})
+}
+
+// startLink saves a new verification code for a member who wants to link an
+// account, and returns the code
+func (m *Module) startLink(ctx context.Context, discordUserID, login string) (string, error) {
+	if _, err := m.api.FetchUser(login); err != nil {
+		return "", fmt.Errorf("%w: %w", errFetchUser, err)
+	}
+
+	b := make([]byte, 4)
+	rand.Read(b)
+	code := fmt.Sprintf("buggins-%s", hex.EncodeToString(b))
+
+	err := m.db.SaveInatAccountVerification(ctx, store.SaveInatAccountVerificationParams{
+		DiscordUserID: discordUserID,
+		InatLogin:     login,
+		Code:          code,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error saving account verification: %w", err)
+	}
+
+	return code, nil
+}
+
+// handleVerify links the account once its bio contains the member's code
+func (m *Module) handleVerify(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	user := mod.InteractionUser(i)
+	verification, account, err := m.verify(context.Background(), user.ID)
+
+	switch {
+	case errors.Is(err, errNoVerification):
+		mod.RespondEphemeral(d, i, "Start by linking your account with `/inat link`.")
+	case errors.Is(err, errFetchUser):
+		m.logger.Warn("error fetching inat user", "login", verification.InatLogin, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, I couldn't reach iNaturalist, please try again later.")
+	case errors.Is(err, errCodeNotFound):
+		mod.RespondEphemeral(d, i, fmt.Sprintf(
+			"I couldn't find `%s` in `%s`'s bio yet. It can take a minute to show up after saving.",
+			verification.Code,
+			account.Login,
+		))
+	case err != nil:
+		m.logger.Error("error verifying account", "user", user.ID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+	default:
+		m.logger.Info("linked account", "user", user.ID, "login", account.Login)
+		mod.RespondEphemeral(d, i, fmt.Sprintf(
+			"You're linked to [%s](https://inaturalist.org/people/%d)! You can remove the code from your bio now.",
+			account.Login,
+			account.ID,
+		))
+	}
+}
+
+// verify links a member's account if its bio contains their code. Codes
+// expire after `verificationTTL`.
+func (m *Module) verify(
+	ctx context.Context,
+	discordUserID string,
+) (store.InatAccountVerification, inat.User, error) {
+	verification, err := m.db.FindInatAccountVerification(ctx, discordUserID)
+
+	if errors.Is(err, sql.ErrNoRows) ||
+		(err == nil && time.Since(verification.CreatedAt) > verificationTTL) {
+		return verification, inat.User{}, errNoVerification
+	}
+
+	if err != nil {
+		return verification, inat.User{}, fmt.Errorf("error fetching account verification: %w", err)
+	}
+
+	account, err := m.api.FetchUser(verification.InatLogin)
+
+	if err != nil {
+		return verification, inat.User{}, fmt.Errorf("%w: %w", errFetchUser, err)
+	}
+
+	if !strings.Contains(account.Description, verification.Code) {
+		return verification, account, errCodeNotFound
+	}
+
+	if err := m.link(ctx, discordUserID, account); err != nil {
+		return verification, account, fmt.Errorf("error linking account: %w", err)
+	}
+
+	return verification, account, nil
+}
+
+// link stores the link between a member and an account. An account can only
+// be linked to one member, so whoever verified it last wins.
+func (m *Module) link(ctx context.Context, discordUserID string, account inat.User) error {
+	if err := m.db.DeleteInatAccountByInatUser(ctx, account.ID); err != nil {
+		return err
+	}
+
+	err := m.db.SaveInatAccount(ctx, store.SaveInatAccountParams{
+		DiscordUserID: discordUserID,
+		InatUserID:    account.ID,
+		InatLogin:     account.Login,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return m.db.DeleteInatAccountVerification(ctx, discordUserID)
+}
+
+func (m *Module) handleUnlink(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	user := mod.InteractionUser(i)
+	rows, err := m.db.DeleteInatAccount(ctx, user.ID)
+
+	if err != nil {
+		m.logger.Error("error unlinking account", "user", user.ID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	if rows <= 0 {
+		mod.RespondEphemeral(d, i, "You don't have a linked iNaturalist account.")
+		return
+	}
+
+	m.logger.Info("unlinked account", "user", user.ID)
+	mod.RespondEphemeral(d, i, "Your iNaturalist account has been unlinked.")
+}
@@ -0,0 +1,142 @@
+package accounts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/store"
+)
+
+func newTestModule(t *testing.T, bio func(code string) string) (*Module, *sql.DB) {
+	t.Helper()
+
+	conn, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.sqlite"))
+
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+
+	t.Cleanup(func() { conn.Close() })
+	goose.SetBaseFS(store.EmbeddedMigrations)
+	goose.SetLogger(goose.NopLogger())
+
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		t.Fatalf("error setting dialect: %v", err)
+	}
+
+	if err := goose.Up(conn, "migrations"); err != nil {
+		t.Fatalf("error running migrations: %v", err)
+	}
+
+	db := store.New(conn)
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /users/{login}", func(w http.ResponseWriter, r *http.Request) {
+		login := r.PathValue("login")
+		verification, _ := db.FindInatAccountVerification(r.Context(), "discord-1")
+
+		json.NewEncoder(w).Encode(inat.UserResult{Results: []inat.User{{
+			ID:          42,
+			Login:       login,
+			Description: bio(verification.Code),
+		}}})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	m, err := New(db, slog.New(slog.DiscardHandler))
+
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	m.api = m.api.WithBaseURL(server.URL)
+	return m, conn
+}
+
+func TestLinkAndVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		bio     func(code string) string
+		expired bool
+		wantErr error
+	}{
+		{
+			name: "code in bio",
+			bio:  func(code string) string { return "i like spiders " + code },
+		},
+		{
+			name:    "code missing from bio",
+			bio:     func(code string) string { return "i like spiders" },
+			wantErr: errCodeNotFound,
+		},
+		{
+			name:    "code expired",
+			bio:     func(code string) string { return code },
+			expired: true,
+			wantErr: errNoVerification,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m, conn := newTestModule(t, tt.bio)
+			code, err := m.startLink(ctx, "discord-1", "spiderfan")
+
+			if err != nil {
+				t.Fatalf("startLink() error: %v", err)
+			}
+
+			if code == "" {
+				t.Fatal("startLink() returned an empty code")
+			}
+
+			if tt.expired {
+				_, err := conn.ExecContext(
+					ctx,
+					"update inat_account_verification set created_at = datetime('now', '-2 hours')",
+				)
+
+				if err != nil {
+					t.Fatalf("error expiring verification: %v", err)
+				}
+			}
+
+			_, account, err := m.verify(ctx, "discord-1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			linked, err := m.db.FindInatAccount(ctx, "discord-1")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, sql.ErrNoRows) {
+					t.Errorf("account was linked: %+v, err %v", linked, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FindInatAccount() error: %v", err)
+			}
+
+			if linked.InatUserID != account.ID || linked.InatLogin != "spiderfan" {
+				t.Errorf("linked %d/%s, want 42/spiderfan", linked.InatUserID, linked.InatLogin)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package mod
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+// Message component custom ids are made up of the module that handles the
+// component, the action to take and any arguments, separated by colons, for
+// example `inatobs:photos:<state id>:2`. State that doesn't fit in the custom
+// id is stored in the database with `SaveComponentState`, so buttons keep
+// working after a restart.
+
+// ComponentID builds a message component custom id
+func ComponentID(module, action string, args ...string) string {
+	return strings.Join(append([]string{module, action}, args...), ":")
+}
+
+// ParseComponentID splits a custom id into the module, action and arguments
+func ParseComponentID(customID string) (string, string, []string, bool) {
+	parts := strings.Split(customID, ":")
+
+	if len(parts) < 2 {
+		return "", "", nil, false
+	}
+
+	return parts[0], parts[1], parts[2:], true
+}
+
+// SaveComponentState stores state for message components and returns its id
+func SaveComponentState(
+	ctx context.Context,
+	db *store.Queries,
+	module string,
+	state any,
+) (string, error) {
+	data, err := json.Marshal(state)
+
+	if err != nil {
+		return "", fmt.Errorf("error encoding component state: %w", err)
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	err = db.CreateComponentState(ctx, store.CreateComponentStateParams{
+		ID:     id,
+		Module: module,
+		Data:   string(data),
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("error saving component state: %w", err)
+	}
+
+	return id, nil
+}
+
+func FetchComponentState[T any](ctx context.Context, db *store.Queries, id string) (T, error) {
+	var state T
+
+	row, err := db.FindComponentState(ctx, id)
+
+	if err != nil {
+		return state, fmt.Errorf("error fetching component state %s: %w", id, err)
+	}
+
+	if err := json.Unmarshal([]byte(row.Data), &state); err != nil {
+		return state, fmt.Errorf("could not parse component state %s: %w", id, err)
+	}
+
+	return state, nil
+}
+
+// PruneComponentStates deletes a module's component state that is older than
+// `maxAge`. Components that used it answer as if it was never saved.
+func PruneComponentStates(
+	ctx context.Context,
+	db *store.Queries,
+	module string,
+	maxAge time.Duration,
+) error {
+	err := db.DeleteComponentStates(ctx, store.DeleteComponentStatesParams{
+		Module:    module,
+		CreatedAt: time.Now().UTC().Add(-maxAge),
+	})
+
+	if err != nil {
+		return fmt.Errorf("error pruning component state: %w", err)
+	}
+
+	return nil
+}
+
+// TakeCooldown starts a cooldown for `key` if there isn't one running
+// already. If there is, it returns how long is left on it.
+func TakeCooldown(
+	ctx context.Context,
+	db *store.Queries,
+	key string,
+	d time.Duration,
+) (time.Duration, error) {
+	now := time.Now().UTC()
+
+	count, err := db.TakeCooldown(ctx, store.TakeCooldownParams{
+		Key:       key,
+		ExpiresAt: now.Add(d),
+		CreatedAt: now,
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("error saving cooldown: %w", err)
+	}
+
+	if count > 0 {
+		return 0, nil
+	}
+
+	row, err := db.FindCooldown(ctx, key)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("error fetching cooldown: %w", err)
+	}
+
+	return max(row.ExpiresAt.Sub(now), time.Second), nil
+}
+
+// ReleaseCooldown ends the cooldown for `key`, for when whatever it was taken
+// for didn't happen
+func ReleaseCooldown(ctx context.Context, db *store.Queries, key string) error {
+	if err := db.DeleteCooldown(ctx, key); err != nil {
+		return fmt.Errorf("error releasing cooldown: %w", err)
+	}
+
+	return nil
+}
@@ -14,7 +14,7 @@ type ConfigCommandOptions struct {
 	Args       []*glap.Arg
 	GetData    func(m *glap.Matches) any
 	ModuleName string
-	KeyArg     string
+	KeyArgs    []string
 	GetKey     func(m *glap.Matches) string
 }
 
@@ -21,7 +21,7 @@ func ConfigCommandOptions() mod.ConfigCommandOptions {
 
 	return mod.ConfigCommandOptions{
 		Args:       args,
-		KeyArg:     "guild-id",
+		KeyArgs:    []string{"guild-id"},
 		ModuleName: moduleName,
 		GetKey: func(m *glap.Matches) string {
 			v, _ := m.GetString("guild-id")
@@ -2,10 +2,10 @@ package featured
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"strings"
 	"sync"
 
@@ -21,13 +21,23 @@ var (
 
 type Module struct {
 	db         *store.Queries
+	outbox     *mod.Outbox
 	logger     *slog.Logger
 	config     []GuildConfig
 	configLock sync.RWMutex
 }
 
-func New(db *store.Queries, logger *slog.Logger) (*Module, error) {
-	return &Module{db: db, logger: logger}, nil
+// featuredMeta identifies the message a queued Hall of Fame post is for
+type featuredMeta struct {
+	GuildID   string `json:"guild_id"`
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+}
+
+func New(db *store.Queries, outbox *mod.Outbox, logger *slog.Logger) (*Module, error) {
+	m := &Module{db: db, outbox: outbox, logger: logger}
+	outbox.OnDelivered(moduleName, m.markMessageAsFeatured)
+	return m, nil
 }
 
 func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
@@ -44,8 +54,12 @@ func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *stor
 	return nil
 }
 
-func Provider(db *store.Queries, logger *slog.Logger) (mod.ModuleProviderResult, error) {
-	module, err := New(db, logger.With("mod", moduleName))
+func Provider(
+	db *store.Queries,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, outbox, logger.With("mod", moduleName))
 
 	if err != nil {
 		return mod.ModuleProviderResult{}, err
@@ -148,53 +162,31 @@ func (m *Module) registerHandlers(discord *discordgo.Session) {
 				return
 			}
 
-			_, err = m.db.SaveFeaturedMessage(
-				context.Background(),
-				store.SaveFeaturedMessageParams{
-					ChannelID: r.ChannelID,
-					MessageID: r.MessageID,
-					GuildID:   r.GuildID,
-				},
-			)
-
-			if err != nil {
-				m.logger.Warn(
-					"couldn't save featured message to db",
-					"channel",
-					r.ChannelID,
-					"message",
-					r.MessageID,
-					"err",
-					err,
-				)
-				return
-			}
-
-			files := make([]*discordgo.File, 0, len(msg.Attachments))
+			files := make([]mod.OutboxFile, 0, len(msg.Attachments))
 
 			for _, a := range msg.Attachments {
 				if !strings.Contains(a.ContentType, "image") {
 					continue
 				}
 
-				r, err := http.Get(a.URL)
-
-				if err != nil {
-					m.logger.Error("unable to retrieve data for photo", "url", a.URL, "err", err)
-					continue
-				}
-
-				defer r.Body.Close()
-				files = append(files, &discordgo.File{
+				files = append(files, mod.OutboxFile{
+					URL:         a.URL,
 					Name:        a.Filename,
 					ContentType: a.ContentType,
-					Reader:      r.Body,
 				})
 			}
 
-			discord.ChannelMessageSendComplex(
-				config.ChannelID,
-				&discordgo.MessageSend{
+			queued, err := m.outbox.Enqueue(context.Background(), mod.Delivery{
+				Module:    moduleName,
+				Kind:      moduleName,
+				ChannelID: config.ChannelID,
+				DedupeKey: fmt.Sprintf("%s:%s:%s:%s", moduleName, r.GuildID, r.ChannelID, r.MessageID),
+				Meta: featuredMeta{
+					GuildID:   r.GuildID,
+					ChannelID: r.ChannelID,
+					MessageID: r.MessageID,
+				},
+				Message: mod.OutboxMessage{
 					Content: fmt.Sprintf(
 						":partying_face: Congratulations, <@%s>, your [post](https://discord.com/channels/@me/%s/%s) made the Hall of Fame!",
 						msg.Author.ID,
@@ -203,12 +195,61 @@ func (m *Module) registerHandlers(discord *discordgo.Session) {
 					),
 					Files: files,
 				},
-			)
+			})
+
+			if err != nil {
+				m.logger.Warn(
+					"couldn't queue featured message",
+					"channel",
+					r.ChannelID,
+					"message",
+					r.MessageID,
+					"err",
+					err,
+				)
+				return
+			}
+
+			if !queued {
+				m.logger.Info(
+					"message is already queued to be featured, skipping",
+					"channel",
+					r.ChannelID,
+					"message",
+					r.MessageID,
+				)
+			}
 		}
 
 	})
 }
 
+// markMessageAsFeatured records a message as featured once its Hall of Fame
+// post has been delivered
+func (m *Module) markMessageAsFeatured(
+	ctx context.Context,
+	data []byte,
+	_ *discordgo.Message,
+) error {
+	var meta featuredMeta
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("could not parse featured message meta: %w", err)
+	}
+
+	_, err := m.db.SaveFeaturedMessage(ctx, store.SaveFeaturedMessageParams{
+		ChannelID: meta.ChannelID,
+		MessageID: meta.MessageID,
+		GuildID:   meta.GuildID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("couldn't save featured message to db: %w", err)
+	}
+
+	return nil
+}
+
 func imageAttachmentCount(attachments []*discordgo.MessageAttachment) int {
 	if len(attachments) < 1 {
 		return 0
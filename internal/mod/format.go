@@ -0,0 +1,13 @@
+package mod
+
+import (
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var countPrinter = message.NewPrinter(language.English)
+
+// FormatCount formats a count with thousands separators, like `12,345`
+func FormatCount(n int64) string {
+	return countPrinter.Sprintf("%d", n)
+}
@@ -0,0 +1,139 @@
+package inatlookup
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+// registerSlashCommands adds `/taxon`, which works without the message content
+// intent that prefix commands need, and handles the taxon picker
+func (m *Module) registerSlashCommands(discord *discordgo.Session) {
+	m.slashCommandsRegistered = true
+	command := discordgo.ApplicationCommand{
+		Name:        "taxon",
+		Description: "Look up a taxon on iNaturalist",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "query",
+				Description:  "Taxon to look up",
+				Required:     true,
+				Autocomplete: true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "public",
+				Description: "Show the result to everyone in the channel",
+			},
+		},
+	}
+
+	_, err := discord.ApplicationCommandCreate(discord.State.Application.ID, "", &command)
+
+	if err != nil {
+		m.logger.Warn("error creating command", "command", command.Name, "err", err)
+	}
+
+	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			if i.ApplicationCommandData().Name == command.Name {
+				m.handleTaxon(d, i)
+			}
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			if i.ApplicationCommandData().Name == command.Name {
+				m.handleTaxonAutocomplete(d, i)
+			}
+		case discordgo.InteractionMessageComponent:
+			module, action, args, ok := mod.ParseComponentID(i.MessageComponentData().CustomID)
+
+			if ok && module == moduleName && action == "pick" && len(args) > 0 {
+				m.handlePick(d, i, args[0])
+			}
+		}
+	})
+
+	m.logger.Info(" -> inatlookup slash commands registered")
+}
+
+func (m *Module) handleTaxon(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	var query string
+	var flags discordgo.MessageFlags = discordgo.MessageFlagsEphemeral
+
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "query":
+			query = strings.TrimSpace(option.StringValue())
+		case "public":
+			if option.BoolValue() {
+				flags = 0
+			}
+		}
+	}
+
+	// guilds that aren't configured, and dms, get the defaults
+	config, _ := m.guildConfig(i.GuildID)
+
+	if !config.commandEnabled("t") ||
+		len(config.Channels) > 0 && !slices.Contains(config.Channels, i.ChannelID) {
+		mod.RespondEphemeral(d, i, "Taxon lookups aren't available here.")
+		return
+	}
+
+	// looking up the taxon and its local count can take longer than discord
+	// waits for a response
+	err := d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to /taxon", "err", err)
+		return
+	}
+
+	go func() {
+		if _, err := d.InteractionResponseEdit(i.Interaction, m.taxonResponse(config, query)); err != nil {
+			m.logger.Error("error responding to /taxon", "err", err)
+		}
+	}()
+}
+
+// taxonResponse looks up a taxon for `/taxon`
+func (m *Module) taxonResponse(config GuildConfig, query string) *discordgo.WebhookEdit {
+	l := m.localized(config)
+	taxon, err := mod.ResolveTaxon(l.api, query)
+
+	if err != nil || query == "" {
+		content := notFound
+		return &discordgo.WebhookEdit{Content: &content}
+	}
+
+	return &discordgo.WebhookEdit{Embeds: &[]*discordgo.MessageEmbed{l.taxonEmbed(taxon)}}
+}
+
+func (m *Module) handleTaxonAutocomplete(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	var query string
+
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == "query" && option.Focused {
+			query = option.StringValue()
+		}
+	}
+
+	config, _ := m.guildConfig(i.GuildID)
+	choices, err := mod.TaxonChoices(m.localized(config).api, query)
+
+	if err != nil {
+		m.logger.Warn("error autocompleting taxa", "query", query, "err", err)
+	}
+
+	d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+}
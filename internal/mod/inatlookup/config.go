@@ -5,16 +5,50 @@ import (
 	"slices"
 
 	"github.com/synic/glap"
+	"golang.org/x/text/language"
 
 	"github.com/synic/buggins/internal/mod"
 )
 
 type GuildConfig struct {
 	CommandPrefixRegex *regexp.Regexp `json:"-"`
+	InlineRegex        *regexp.Regexp `json:"-"`
 	Name               string         `json:"name"`
 	ID                 string         `json:"id"`
 	CommandPrefix      string         `json:"command_prefix"`
 	Channels           []string       `json:"channels"`
+	// InlineSyntax surrounds inline taxon lookups, either one delimiter for
+	// both sides like `.`, or an opening and closing delimiter separated by a
+	// space like `[[ ]]`
+	InlineSyntax string `json:"inline_syntax"`
+	// InlineSingleWords allows one word inline lookups, which are otherwise
+	// ignored because ordinary punctuation matches them too easily
+	InlineSingleWords bool `json:"inline_single_words"`
+	// Commands are the lookup commands the guild has enabled, all of them
+	// when empty
+	Commands []string `json:"commands"`
+	// Unfurl replies to iNaturalist links with an embed, channels can opt in
+	// or out on their own with UnfurlChannels and NoUnfurlChannels
+	Unfurl           bool     `json:"unfurl"`
+	UnfurlChannels   []string `json:"unfurl_channels"`
+	NoUnfurlChannels []string `json:"no_unfurl_channels"`
+	// Locale is the language common names and numbers are shown in, like
+	// `de` or `pt-BR`, and PreferredPlaceID picks the common names used in a
+	// place and adds local observation counts
+	Locale           string `json:"locale"`
+	PreferredPlaceID int64  `json:"preferred_place_id"`
+}
+
+func (c GuildConfig) commandEnabled(command string) bool {
+	return len(c.Commands) <= 0 || slices.Contains(c.Commands, command)
+}
+
+func (c GuildConfig) unfurls(channelID string) bool {
+	if slices.Contains(c.NoUnfurlChannels, channelID) {
+		return false
+	}
+
+	return c.Unfurl || slices.Contains(c.UnfurlChannels, channelID)
 }
 
 func ConfigCommandOptions() mod.ConfigCommandOptions {
@@ -22,11 +56,44 @@ func ConfigCommandOptions() mod.ConfigCommandOptions {
 		glap.NewArg("guild-id").Short('g').Required(true).Help("Guild GUILD_ID"),
 		glap.NewArg("command-prefix").Default(",").Help("Command prefix PREFIX"),
 		glap.NewArg("channels").Short('c').Action(glap.Append).Help("Channel ids (omit for all channels) CHANNEL_IDS"),
+		glap.NewArg("inline-syntax").
+			Default(".").
+			Validator(func(v string) error {
+				_, err := inlineRegex(v, false)
+				return err
+			}).
+			Help("Delimiters around inline lookups, like \".\" or \"[[ ]]\" SYNTAX"),
+		glap.NewArg("inline-single-words").
+			Default("false").
+			PossibleValues("true", "false").
+			Help("Also look up single words, like .Phidippus., inline BOOL"),
+		glap.NewArg("commands").
+			Action(glap.Append).
+			PossibleValues(append(commands, "all")...).
+			Help("Lookup commands to enable (omit for all commands) COMMANDS"),
+		glap.NewArg("locale").
+			Validator(func(v string) error {
+				_, err := language.Parse(v)
+				return err
+			}).
+			Help("Language for common names and numbers, defaults to English LOCALE"),
+		glap.NewArg("preferred-place-id").
+			Help("iNaturalist place for local common names and observation counts PLACE_ID"),
+		glap.NewArg("unfurl").
+			Default("false").
+			PossibleValues("true", "false").
+			Help("Reply to iNaturalist links with an embed BOOL"),
+		glap.NewArg("unfurl-channels").
+			Action(glap.Append).
+			Help("Channels to unfurl links in, even if unfurl is off CHANNEL_IDS"),
+		glap.NewArg("no-unfurl-channels").
+			Action(glap.Append).
+			Help("Channels to never unfurl links in CHANNEL_IDS"),
 	}
 
 	return mod.ConfigCommandOptions{
 		Args:       args,
-		KeyArg:     "guild-id",
+		KeyArgs:    []string{"guild-id"},
 		ModuleName: moduleName,
 		GetKey: func(m *glap.Matches) string {
 			v, _ := m.GetString("guild-id")
@@ -35,16 +102,36 @@ func ConfigCommandOptions() mod.ConfigCommandOptions {
 		GetData: func(m *glap.Matches) any {
 			guildID, _ := m.GetString("guild-id")
 			commandPrefix, _ := m.GetString("command-prefix")
+			inlineSyntax, _ := m.GetString("inline-syntax")
+			inlineSingleWords, _ := m.GetString("inline-single-words")
 			channels, _ := m.GetStringSlice("channels")
+			enabled, _ := m.GetStringSlice("commands")
+			locale, _ := m.GetString("locale")
+			preferredPlaceID, _ := m.GetInt64("preferred-place-id")
+			unfurl, _ := m.GetString("unfurl")
+			unfurlChannels, _ := m.GetStringSlice("unfurl-channels")
+			noUnfurlChannels, _ := m.GetStringSlice("no-unfurl-channels")
 
 			if slices.Contains(channels, "all") {
 				channels = []string{}
 			}
 
+			if slices.Contains(enabled, "all") {
+				enabled = []string{}
+			}
+
 			return GuildConfig{
-				ID:            guildID,
-				CommandPrefix: commandPrefix,
-				Channels:      channels,
+				ID:                guildID,
+				CommandPrefix:     commandPrefix,
+				InlineSyntax:      inlineSyntax,
+				InlineSingleWords: inlineSingleWords == "true",
+				Channels:          channels,
+				Commands:          enabled,
+				Locale:            locale,
+				PreferredPlaceID:  preferredPlaceID,
+				Unfurl:            unfurl == "true",
+				UnfurlChannels:    unfurlChannels,
+				NoUnfurlChannels:  noUnfurlChannels,
 			}
 		},
 	}
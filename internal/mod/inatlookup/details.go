@@ -0,0 +1,143 @@
+package inatlookup
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/synic/buggins/internal/inat"
+)
+
+var (
+	htmlTagRe       = regexp.MustCompile(`<[^>]*>`)
+	htmlLineBreakRe = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlParagraphRe = regexp.MustCompile(`(?i)</p>`)
+	// the ranks shown in the taxonomy breadcrumb
+	breadcrumbRanks = []string{"order", "family", "genus"}
+	// how many similar species are listed
+	maxSimilarSpecies = 5
+	maxSummaryLength  = 400
+)
+
+// lookupTaxonDetails shows a taxon with its taxonomy, wikipedia summary,
+// conservation status and the species it's most often confused with
+func (m *Module) lookupTaxonDetails(
+	l localized,
+	msg *discordgo.Message,
+	content string,
+) *discordgo.MessageSend {
+	r, err := l.api.Search([]string{"taxa"}, content)
+
+	if err != nil || len(r.Results) <= 0 {
+		return textReply(notFound)
+	}
+
+	// searches leave out the ancestry and the rest, fetch the taxon itself
+	taxon, err := l.api.FetchTaxon(r.Results[0].Record.ID)
+
+	if err != nil {
+		m.logger.Error("error fetching taxon", "taxon", r.Results[0].Record.ID, "err", err)
+		return textReply(somethingWentWrong)
+	}
+
+	similar, err := l.api.FetchSimilarSpecies(taxon.ID)
+
+	if err != nil {
+		// the details are still worth showing without them
+		m.logger.Warn("error fetching similar species", "taxon", taxon.ID, "err", err)
+	}
+
+	return embedReply(l.taxonDetailsEmbed(taxon, similar.Results))
+}
+
+func (l localized) taxonDetailsEmbed(t inat.Taxon, similar []inat.SimilarSpecies) *discordgo.MessageEmbed {
+	p := l.printer
+	title := cases.Title(language.English, cases.Compact)
+	var description []string
+
+	if breadcrumb := taxonBreadcrumb(t); breadcrumb != "" {
+		description = append(description, breadcrumb)
+	}
+
+	if summary := summarize(stripHTML(t.WikipediaSummary), maxSummaryLength); summary != "" {
+		description = append(description, summary)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color:       embedColor,
+		Title:       t.DisplayName(),
+		URL:         fmt.Sprintf("https://inaturalist.org/taxa/%d", t.ID),
+		Description: strings.Join(description, "\n\n"),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Rank", Value: title.String(t.Rank), Inline: true},
+			{Name: "Observations", Value: p.Sprintf("%d", t.ObservationCount), Inline: true},
+		},
+	}
+
+	if photo := t.DefaultPhoto.Medium(); photo != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: photo}
+	}
+
+	if status := t.ConservationStatus; status != nil && status.StatusName != "" {
+		value := title.String(status.StatusName)
+
+		if status.Authority != "" {
+			value = fmt.Sprintf("%s (%s)", value, status.Authority)
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   "Conservation Status",
+			Value:  value,
+			Inline: true,
+		})
+	}
+
+	l.addLocalCount(embed, t.ID)
+	lines := make([]string, 0, maxSimilarSpecies)
+
+	for _, s := range similar[:min(len(similar), maxSimilarSpecies)] {
+		lines = append(lines, p.Sprintf(
+			"[%s](https://inaturalist.org/taxa/%d) - %d mix-ups",
+			s.Taxon.DisplayName(),
+			s.Taxon.ID,
+			s.Count,
+		))
+	}
+
+	if len(lines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Often Confused With",
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	return embed
+}
+
+// taxonBreadcrumb shows where a taxon sits, like `Araneae › Salticidae ›
+// Phidippus`
+func taxonBreadcrumb(t inat.Taxon) string {
+	var names []string
+
+	for _, ancestor := range t.Ancestors {
+		if slices.Contains(breadcrumbRanks, ancestor.Rank) {
+			names = append(names, ancestor.Name)
+		}
+	}
+
+	return strings.Join(names, " › ")
+}
+
+// stripHTML turns html into plain text, keeping paragraphs on their own
+// lines
+func stripHTML(s string) string {
+	s = htmlLineBreakRe.ReplaceAllString(s, " ")
+	s = htmlParagraphRe.ReplaceAllString(s, "\n")
+	return strings.TrimSpace(html.UnescapeString(htmlTagRe.ReplaceAllString(s, "")))
+}
@@ -0,0 +1,139 @@
+package inatlookup
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/synic/buggins/internal/inat"
+)
+
+var (
+	defaultInlineSyntax = "."
+	// how many inline lookups are answered per message
+	maxInlineLookups = 5
+)
+
+// inlineRegex compiles the regex for a guild's inline lookup syntax. Lookups
+// are two to four words, like `.Phidippus audax.` or `.Pica hudsonia
+// hudsonia.`, and have to start a line or follow a space. Single words, like
+// `.Phidippus.`, are too easily matched by accident, so guilds opt in to
+// them with `singleWords`.
+func inlineRegex(syntax string, singleWords bool) (*regexp.Regexp, error) {
+	delimiters := strings.Fields(syntax)
+
+	if len(delimiters) <= 0 {
+		delimiters = []string{defaultInlineSyntax}
+	}
+
+	if len(delimiters) > 2 {
+		return nil, errors.New("inline syntax is one delimiter, or two separated by a space")
+	}
+
+	minWords := 1
+
+	if singleWords {
+		minWords = 0
+	}
+
+	return regexp.Compile(fmt.Sprintf(
+		`(?m)(?:^|[\s(])%s(\w[\w'-]*(?: [\w'-]+){%d,3})%s`,
+		regexp.QuoteMeta(delimiters[0]),
+		minWords,
+		regexp.QuoteMeta(delimiters[len(delimiters)-1]),
+	))
+}
+
+// inlineQueries returns the distinct inline lookups in a message
+func inlineQueries(re *regexp.Regexp, content string) []string {
+	var queries []string
+
+	for _, idx := range re.FindAllStringSubmatchIndex(content, -1) {
+		// the closing delimiter has to end the word, so the `.` in something
+		// like `.net.au` isn't one
+		if r, _ := utf8.DecodeRuneInString(content[idx[1]:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+
+		query := content[idx[2]:idx[3]]
+
+		if slices.ContainsFunc(queries, func(q string) bool { return strings.EqualFold(q, query) }) {
+			continue
+		}
+
+		queries = append(queries, query)
+
+		if len(queries) >= maxInlineLookups {
+			break
+		}
+	}
+
+	return queries
+}
+
+// lookupInline answers every inline lookup in a message with one reply. A
+// single lookup gets the full taxon embed.
+func (m *Module) lookupInline(l localized, msg *discordgo.Message, queries []string) *discordgo.MessageSend {
+	if len(queries) == 1 {
+		return m.lookupTaxa(l, msg, queries[0])
+	}
+
+	var wg sync.WaitGroup
+	taxa := make([]*inat.Taxon, len(queries))
+
+	for n, query := range queries {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			r, err := l.api.Search([]string{"taxa"}, query)
+
+			if err != nil {
+				m.logger.Warn("error searching taxa", "query", query, "err", err)
+				return
+			}
+
+			if len(r.Results) > 0 {
+				taxon := r.Results[0].Record.Taxon()
+				taxa[n] = &taxon
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	p := l.printer
+	lines := make([]string, 0, len(queries))
+	embed := &discordgo.MessageEmbed{Color: embedColor}
+
+	for n, taxon := range taxa {
+		if taxon == nil {
+			lines = append(lines, fmt.Sprintf("**%s**: nothing found", queries[n]))
+			continue
+		}
+
+		lines = append(lines, p.Sprintf(
+			"**[%s](https://inaturalist.org/taxa/%d)**\n%s, %d observations",
+			taxon.DisplayName(),
+			taxon.ID,
+			cases.Title(language.English, cases.Compact).String(taxon.Rank),
+			taxon.ObservationCount,
+		))
+
+		if embed.Thumbnail == nil && taxon.DefaultPhoto.Medium() != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: taxon.DefaultPhoto.Medium()}
+		}
+	}
+
+	embed.Description = strings.Join(lines, "\n\n")
+	return embedReply(embed)
+}
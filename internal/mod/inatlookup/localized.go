@@ -0,0 +1,58 @@
+package inatlookup
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/synic/buggins/internal/inat"
+)
+
+// localized is what a lookup needs to answer in a guild's language, with
+// counts for the guild's place
+type localized struct {
+	api     inat.Api
+	printer *message.Printer
+	placeID int64
+}
+
+func (m *Module) localized(config GuildConfig) localized {
+	tag, err := language.Parse(config.Locale)
+
+	if err != nil || config.Locale == "" {
+		tag = language.English
+	}
+
+	return localized{
+		api:     m.api.Localized(config.Locale, config.PreferredPlaceID),
+		printer: message.NewPrinter(tag),
+		placeID: config.PreferredPlaceID,
+	}
+}
+
+// addLocalCount adds how often a taxon has been observed in the guild's place
+// to its embed. Guilds without a place don't get one.
+func (l localized) addLocalCount(embed *discordgo.MessageEmbed, taxonID int64) {
+	if l.placeID == 0 {
+		return
+	}
+
+	r, err := l.api.FetchObservations(url.Values{
+		"taxon_id": {strconv.FormatInt(taxonID, 10)},
+		"place_id": {strconv.FormatInt(l.placeID, 10)},
+		"per_page": {"0"},
+	})
+
+	if err != nil {
+		return
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:   "Local Observations",
+		Value:  l.printer.Sprintf("%d", r.TotalResults),
+		Inline: true,
+	})
+}
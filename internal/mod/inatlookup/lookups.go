@@ -0,0 +1,265 @@
+package inatlookup
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+)
+
+var (
+	observationIDRe = regexp.MustCompile(`^(?:https?://(?:www\.)?inaturalist\.org/observations/)?(\d+)/?$`)
+	projectSlugRe   = regexp.MustCompile(`^(?:https?://(?:www\.)?inaturalist\.org/projects/)?([\w-]+)/?$`)
+	qualityGrades   = map[string]string{
+		"research": "Research Grade",
+		"needs_id": "Needs ID",
+		"casual":   "Casual",
+	}
+	// how many species a place lookup lists
+	maxPlaceSpecies = 5
+)
+
+// lookupObservation shows an observation, by id or url
+func (m *Module) lookupObservation(l localized, msg *discordgo.Message, content string) *discordgo.MessageSend {
+	matches := observationIDRe.FindStringSubmatch(strings.TrimSpace(content))
+
+	if matches == nil {
+		return textReply("Sorry, that doesn't look like an observation")
+	}
+
+	r, err := l.api.FetchObservations(url.Values{"id": {matches[1]}})
+
+	if err != nil || len(r.Results) <= 0 {
+		return textReply(notFound)
+	}
+
+	return embedReply(l.observationEmbed(r.Results[0]))
+}
+
+func (l localized) observationEmbed(o inat.Observation) *discordgo.MessageEmbed {
+	p := l.printer
+	taxon := inat.Taxon{
+		Name: o.Taxon.Name,
+		Taxa: inat.Taxa{PreferredCommonName: o.Taxon.PreferredCommonName},
+	}
+	title := taxon.DisplayName()
+
+	if title == "" {
+		title = "Unknown"
+	}
+
+	grade, ok := qualityGrades[o.QualityGrade]
+
+	if !ok {
+		grade = "Unknown"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: embedColor,
+		Title: title,
+		URL:   fmt.Sprintf("https://inaturalist.org/observations/%d", o.ID),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Observer", Value: o.User.DisplayName(), Inline: true},
+			{Name: "Observed", Value: valueOrUnknown(o.ObservedOn), Inline: true},
+			{Name: "Place", Value: valueOrUnknown(o.PlaceGuess)},
+			{Name: "Status", Value: grade, Inline: true},
+			{Name: "Identifications", Value: p.Sprintf("%d", o.IdentificationsCount), Inline: true},
+			{Name: "Faves", Value: p.Sprintf("%d", o.FavesCount), Inline: true},
+		},
+	}
+
+	// photos with all rights reserved are only linked to
+	if photos := o.LicensedPhotos(); len(photos) > 0 {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: photos[0].Medium()}
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: photos[0].Attribution}
+	}
+
+	return embed
+}
+
+// lookupPlace shows a place, with its most observed species
+func (m *Module) lookupPlace(l localized, msg *discordgo.Message, content string) *discordgo.MessageSend {
+	places, err := l.api.AutocompletePlaces(strings.TrimSpace(content))
+
+	if err != nil || len(places.Results) <= 0 {
+		return textReply(notFound)
+	}
+
+	place := places.Results[0]
+	placeID := strconv.FormatInt(place.ID, 10)
+	observations, err := l.api.FetchObservations(url.Values{
+		"place_id": {placeID},
+		"per_page": {"0"},
+	})
+
+	if err != nil {
+		m.logger.Error("error fetching place observations", "place", place.ID, "err", err)
+		return textReply(somethingWentWrong)
+	}
+
+	species, err := l.api.FetchSpeciesCounts(url.Values{
+		"place_id": {placeID},
+		"per_page": {strconv.Itoa(maxPlaceSpecies)},
+	})
+
+	if err != nil {
+		m.logger.Error("error fetching place species", "place", place.ID, "err", err)
+		return textReply(somethingWentWrong)
+	}
+
+	p := l.printer
+	placeURL := fmt.Sprintf("https://inaturalist.org/places/%d", place.ID)
+	name := place.DisplayName
+
+	if name == "" {
+		name = place.Name
+	}
+
+	lines := make([]string, 0, len(species.Results))
+
+	for n, s := range species.Results {
+		taxon := inat.Taxon{
+			Name: s.Taxon.Name,
+			Taxa: inat.Taxa{PreferredCommonName: s.Taxon.PreferredCommonName},
+		}
+
+		lines = append(lines, p.Sprintf(
+			"%d. [%s](https://inaturalist.org/taxa/%d) - %d observations",
+			n+1,
+			taxon.DisplayName(),
+			s.Taxon.ID,
+			s.Count,
+		))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Color: embedColor,
+		Title: name,
+		URL:   placeURL,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Observations", Value: p.Sprintf("%d", observations.TotalResults), Inline: true},
+			{Name: "Species", Value: p.Sprintf("%d", species.TotalResults), Inline: true},
+		},
+	}
+
+	if len(lines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "Top Species",
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "iNaturalist Link",
+		Value: placeURL,
+	})
+
+	return embedReply(embed)
+}
+
+// lookupProject shows a project's stats, by slug or url
+func (m *Module) lookupProject(l localized, msg *discordgo.Message, content string) *discordgo.MessageSend {
+	matches := projectSlugRe.FindStringSubmatch(strings.TrimSpace(content))
+
+	if matches == nil {
+		return textReply("Sorry, that doesn't look like a project")
+	}
+
+	project, err := l.api.FetchProjectBySlug(matches[1])
+
+	if err != nil {
+		return textReply(notFound)
+	}
+
+	embed, err := l.projectEmbed(project)
+
+	if err != nil {
+		m.logger.Error("error fetching project stats", "project", project.ID, "err", err)
+		return textReply(somethingWentWrong)
+	}
+
+	return embedReply(embed)
+}
+
+func (l localized) projectEmbed(project inat.Project) (*discordgo.MessageEmbed, error) {
+	params := url.Values{
+		"project_id": {strconv.FormatInt(project.ID, 10)},
+		"per_page":   {"1"},
+	}
+
+	observations, err := l.api.FetchObservations(params)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching observations: %w", err)
+	}
+
+	species, err := l.api.FetchSpeciesCounts(params)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching species: %w", err)
+	}
+
+	observers, err := l.api.FetchObservers(params)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching observers: %w", err)
+	}
+
+	members, err := l.api.FetchProjectMembers(project.ID, url.Values{"per_page": {"1"}})
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching members: %w", err)
+	}
+
+	p := l.printer
+	projectURL := fmt.Sprintf("https://inaturalist.org/projects/%s", project.Slug)
+
+	embed := &discordgo.MessageEmbed{
+		Color:       embedColor,
+		Title:       project.Title,
+		URL:         projectURL,
+		Description: summarize(stripHTML(project.Description), 300),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Observations", Value: p.Sprintf("%d", observations.TotalResults), Inline: true},
+			{Name: "Species", Value: p.Sprintf("%d", species.TotalResults), Inline: true},
+			{Name: "Observers", Value: p.Sprintf("%d", observers.TotalResults), Inline: true},
+			{Name: "Members", Value: p.Sprintf("%d", members.TotalResults), Inline: true},
+			{Name: "iNaturalist Link", Value: projectURL},
+		},
+	}
+
+	if project.IconURL != "" {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: project.IconURL}
+	}
+
+	return embed, nil
+}
+
+func valueOrUnknown(v string) string {
+	if strings.TrimSpace(v) == "" {
+		return "Unknown"
+	}
+
+	return v
+}
+
+// summarize trims text down to its first paragraph and at most length
+// characters
+func summarize(text string, length int) string {
+	text = strings.TrimSpace(text)
+
+	if before, _, ok := strings.Cut(text, "\n"); ok {
+		text = strings.TrimSpace(before)
+	}
+
+	if r := []rune(text); len(r) > length {
+		return strings.TrimSpace(string(r[:length-1])) + "…"
+	}
+
+	return text
+}
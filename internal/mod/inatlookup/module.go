@@ -2,17 +2,18 @@ package inatlookup
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
 	"slices"
+	"strings"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
-	"golang.org/x/text/message"
 
 	"github.com/synic/buggins/internal/inat"
 	"github.com/synic/buggins/internal/mod"
@@ -21,24 +22,57 @@ import (
 
 var (
 	moduleName         = "inatlookup"
-	inlineTaxaSearchRe = regexp.MustCompile(`(?m) \.(\w+ ?\w+?)\. `)
+	embedColor         = 5763719
+	notFound           = "Sorry, nothing could be found for that request"
+	somethingWentWrong = "Sorry, something went wrong"
+	// commands are the prefix commands, `t` also covers inline lookups
+	commands = []string{"t", "tt", "u", "o", "p", "proj"}
 )
 
-type commandHandler = func(*discordgo.Session, *discordgo.MessageCreate, string)
+// commandHandler answers a lookup command. The reply isn't sent by the
+// handler, so it can also be used to edit an earlier reply.
+type commandHandler = func(localized, *discordgo.Message, string) *discordgo.MessageSend
 
 type Module struct {
-	api        inat.Api
-	logger     *slog.Logger
-	config     []GuildConfig
-	configLock sync.RWMutex
+	api                     inat.Api
+	db                      *store.Queries
+	scheduler               *mod.Scheduler
+	logger                  *slog.Logger
+	config                  []GuildConfig
+	handlers                map[string]commandHandler
+	replies                 *replyCache
+	handlersRegistered      bool
+	slashCommandsRegistered bool
+	configLock              sync.RWMutex
 }
 
-func New(logger *slog.Logger) (*Module, error) {
-	return &Module{api: inat.New(), logger: logger}, nil
+func New(db *store.Queries, scheduler *mod.Scheduler, logger *slog.Logger) (*Module, error) {
+	m := &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		logger:    logger,
+		replies:   newReplyCache(maxCachedReplies),
+	}
+
+	m.handlers = map[string]commandHandler{
+		"t":    m.lookupTaxa,
+		"tt":   m.lookupTaxonDetails,
+		"u":    m.lookupUser,
+		"o":    m.lookupObservation,
+		"p":    m.lookupPlace,
+		"proj": m.lookupProject,
+	}
+
+	return m, nil
 }
 
-func Provider(logger *slog.Logger) (mod.ModuleProviderResult, error) {
-	module, err := New(logger.With("mod", moduleName))
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, logger.With("mod", moduleName))
 
 	if err != nil {
 		return mod.ModuleProviderResult{}, err
@@ -81,6 +115,12 @@ func (m *Module) SetConfig(config []GuildConfig) {
 		}
 
 		guild.CommandPrefixRegex = re
+		guild.InlineRegex, err = inlineRegex(guild.InlineSyntax, guild.InlineSingleWords)
+
+		if err != nil {
+			m.logger.Info("error compiling inline lookup regex", "guild", guild.ID, "err", err)
+		}
+
 		m.config[i] = guild
 	}
 }
@@ -91,7 +131,17 @@ func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *stor
 		return err
 	}
 	m.SetConfig(config)
-	m.registerHandlers(discord)
+
+	if !m.handlersRegistered {
+		m.registerHandlers(discord)
+	}
+
+	m.schedulePruning(ctx)
+
+	if !m.slashCommandsRegistered {
+		m.registerSlashCommands(discord)
+	}
+
 	m.logger.Info("started module")
 	m.logger.Info(" -> config", "guilds", m.Config())
 	return nil
@@ -117,12 +167,7 @@ func (m *Module) ReloadConfig(
 }
 
 func (m *Module) registerHandlers(discord *discordgo.Session) {
-	m.configLock.Lock()
-	defer m.configLock.Unlock()
-
-	handlers := map[string]commandHandler{
-		"t": m.lookupTaxa,
-	}
+	m.handlersRegistered = true
 
 	discord.AddHandler(func(d *discordgo.Session, msg *discordgo.MessageCreate) {
 		config, err := m.guildConfig(msg.GuildID)
@@ -131,81 +176,197 @@ func (m *Module) registerHandlers(discord *discordgo.Session) {
 			return
 		}
 
-		if len(config.Channels) > 0 && !slices.Contains(config.Channels, msg.ChannelID) {
+		send, unfurled := m.respond(config, msg.Message)
+
+		if send == nil {
 			return
 		}
 
-		if config.CommandPrefixRegex == nil {
-			m.logger.Warn("guilddoes not have a valid command prefix", "guild", msg.GuildID)
+		m.reply(d, msg.Message, send)
+
+		if unfurled {
+			m.suppressPreviews(d, msg.Message)
+		}
+	})
+
+	discord.AddHandler(func(d *discordgo.Session, u *discordgo.MessageUpdate) {
+		if u.Message == nil {
 			return
 		}
 
-		matches := config.CommandPrefixRegex.FindStringSubmatch(msg.Content)
+		config, err := m.guildConfig(u.GuildID)
 
-		if matches != nil {
-			command := matches[1]
-			content := matches[2]
+		if err != nil {
+			return
+		}
 
-			handler, ok := handlers[command]
+		m.syncReply(d, config, u.Message)
+	})
 
-			if ok {
-				handler(d, msg, content)
-			}
+	discord.AddHandler(func(d *discordgo.Session, msg *discordgo.MessageDelete) {
+		if _, err := m.guildConfig(msg.GuildID); err != nil {
+			return
 		}
 
-		matches = inlineTaxaSearchRe.FindStringSubmatch(msg.Content)
+		if r, ok := m.findReply(msg.ID); ok {
+			m.deleteReply(d, r)
+		}
+	})
 
-		if matches != nil {
-			handler, ok := handlers["t"]
+	discord.AddHandler(func(d *discordgo.Session, r *discordgo.MessageReactionAdd) {
+		if _, err := m.guildConfig(r.GuildID); err != nil {
+			return
+		}
 
-			if ok {
-				handler(d, msg, matches[1])
-			}
+		if strings.TrimSuffix(r.Emoji.Name, "\ufe0f") == deleteEmoji {
+			m.handleDeleteReaction(d, r)
 		}
 	})
 }
 
-func (m *Module) lookupTaxa(
-	discord *discordgo.Session,
-	msg *discordgo.MessageCreate,
-	content string,
-) {
-	r, err := m.api.Search([]string{"taxa"}, content)
-
-	if err == nil && len(r.Results) > 0 {
-		r := r.Results[0].Record
-		p := message.NewPrinter(language.English)
-
-		discord.ChannelMessageSendComplex(msg.ChannelID, &discordgo.MessageSend{
-			Embed: &discordgo.MessageEmbed{
-				Thumbnail: &discordgo.MessageEmbedThumbnail{URL: r.DefaultPhoto.MediumURL},
-				Color:     5763719,
-				Fields: []*discordgo.MessageEmbedField{
-					{
-						Value: fmt.Sprintf(
-							"**[%s (%s)](https://inaturalist.org/taxa/%d)**",
-							r.Name,
-							r.PreferredCommonName,
-							r.ID,
-						),
-						Inline: true,
-					},
-					{
-						Name:  "Type",
-						Value: cases.Title(language.English, cases.Compact).String(r.Rank),
-					},
-					{
-						Name:  "Observers",
-						Value: p.Sprintf("%d", r.ObservationCount),
-					},
-					{
-						Name:  "iNaturalist Link",
-						Value: fmt.Sprintf("https://inaturalist.org/taxa/%d", r.ID),
-					},
-				},
+// respond works out the reply to a message, if it needs one. Unfurled links
+// are only answered when the message isn't a lookup itself.
+func (m *Module) respond(config GuildConfig, msg *discordgo.Message) (*discordgo.MessageSend, bool) {
+	if len(config.Channels) > 0 && !slices.Contains(config.Channels, msg.ChannelID) {
+		return nil, false
+	}
+
+	if msg.Author == nil || msg.Author.Bot {
+		return nil, false
+	}
+
+	if config.CommandPrefixRegex == nil {
+		m.logger.Warn("guilddoes not have a valid command prefix", "guild", msg.GuildID)
+		return nil, false
+	}
+
+	l := m.localized(config)
+	matches := config.CommandPrefixRegex.FindStringSubmatch(msg.Content)
+
+	if matches != nil {
+		command := matches[1]
+		content := matches[2]
+
+		handler, ok := m.handlers[command]
+
+		if ok && config.commandEnabled(command) {
+			return handler(l, msg, content), false
+		}
+	}
+
+	if config.InlineRegex != nil && config.commandEnabled("t") {
+		if queries := inlineQueries(config.InlineRegex, msg.Content); len(queries) > 0 {
+			return m.lookupInline(l, msg, queries), false
+		}
+	}
+
+	if config.unfurls(msg.ChannelID) {
+		if send := m.unfurl(l, msg); send != nil {
+			return send, true
+		}
+	}
+
+	return nil, false
+}
+
+func (m *Module) lookupTaxa(l localized, msg *discordgo.Message, content string) *discordgo.MessageSend {
+	r, err := l.api.Search([]string{"taxa"}, content)
+
+	if err != nil || len(r.Results) <= 0 {
+		return textReply(notFound)
+	}
+
+	if items := candidates(content, r.Results); len(items) > 1 {
+		return pickerReply(l, msg, items)
+	}
+
+	return embedReply(l.taxonEmbed(r.Results[0].Record.Taxon()))
+}
+
+func (l localized) taxonEmbed(t inat.Taxon) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: t.DefaultPhoto.Medium()},
+		Color:     embedColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{
+				Value: fmt.Sprintf(
+					"**[%s (%s)](https://inaturalist.org/taxa/%d)**",
+					t.Name,
+					t.PreferredCommonName,
+					t.ID,
+				),
+				Inline: true,
+			},
+			{
+				Name:  "Type",
+				Value: cases.Title(language.English, cases.Compact).String(t.Rank),
+			},
+			{
+				Name:  "Observers",
+				Value: l.printer.Sprintf("%d", t.ObservationCount),
+			},
+			{
+				Name:  "iNaturalist Link",
+				Value: fmt.Sprintf("https://inaturalist.org/taxa/%d", t.ID),
 			},
-		})
-	} else {
-		discord.ChannelMessageSend(msg.ChannelID, "Sorry, nothing could be found for that request")
+		},
+	}
+
+	l.addLocalCount(embed, t.ID)
+	return embed
+}
+
+// lookupUser shows an iNaturalist user, by login or by mentioning a member
+// who has linked their account
+func (m *Module) lookupUser(l localized, msg *discordgo.Message, content string) *discordgo.MessageSend {
+	login := strings.TrimPrefix(strings.TrimSpace(content), "@")
+
+	if len(msg.Mentions) > 0 {
+		member := msg.Mentions[0]
+		account, err := m.db.FindInatAccount(context.Background(), member.ID)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return textReply(fmt.Sprintf(
+				"%s hasn't linked their iNaturalist account yet, see `/inat link`",
+				member.Username,
+			))
+		}
+
+		if err != nil {
+			m.logger.Error("error fetching linked account", "user", member.ID, "err", err)
+			return textReply(somethingWentWrong)
+		}
+
+		login = account.InatLogin
+	}
+
+	if login == "" {
+		return textReply(notFound)
+	}
+
+	u, err := l.api.FetchUser(login)
+
+	if err != nil {
+		return textReply(notFound)
+	}
+
+	return embedReply(l.userEmbed(u))
+}
+
+func (l localized) userEmbed(u inat.User) *discordgo.MessageEmbed {
+	p := l.printer
+	profileURL := fmt.Sprintf("https://inaturalist.org/people/%s", u.Login)
+
+	return &discordgo.MessageEmbed{
+		Thumbnail: &discordgo.MessageEmbedThumbnail{URL: u.IconURL},
+		Color:     embedColor,
+		Title:     u.DisplayName(),
+		URL:       profileURL,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Observations", Value: p.Sprintf("%d", u.ObservationsCount), Inline: true},
+			{Name: "Species", Value: p.Sprintf("%d", u.SpeciesCount), Inline: true},
+			{Name: "Identifications", Value: p.Sprintf("%d", u.IdentificationsCount), Inline: true},
+			{Name: "iNaturalist Link", Value: profileURL},
+		},
 	}
 }
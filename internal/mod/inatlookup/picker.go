@@ -0,0 +1,185 @@
+package inatlookup
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+)
+
+var (
+	// how many matches the picker offers
+	maxPickerCandidates = 5
+	// matches scoring at least this close to the top one are considered
+	// just as likely
+	closeScoreRatio = 0.9
+	// discord limits select option labels and descriptions to 100 characters
+	maxOptionLength = 100
+)
+
+// candidates returns the matches to pick from when a search is ambiguous,
+// which is when the top matches score about the same, or when several taxa
+// share the top match's name (a genus in two kingdoms, for example). Returns
+// nothing when the top match is a safe bet.
+func candidates(query string, results []inat.SearchResultItem) []inat.SearchResultItem {
+	var items []inat.SearchResultItem
+
+	for _, item := range results {
+		if item.Type != "" && item.Type != "Taxon" {
+			continue
+		}
+
+		items = append(items, item)
+
+		if len(items) >= maxPickerCandidates {
+			break
+		}
+	}
+
+	if len(items) < 2 {
+		return nil
+	}
+
+	top := items[0]
+	sharedName := false
+
+	for _, item := range items[1:] {
+		if strings.EqualFold(item.Record.Name, top.Record.Name) {
+			sharedName = true
+			break
+		}
+	}
+
+	if sharedName {
+		return items
+	}
+
+	if strings.EqualFold(top.Record.Name, strings.TrimSpace(query)) {
+		return nil
+	}
+
+	if top.Score > 0 && items[1].Score >= top.Score*closeScoreRatio {
+		return items
+	}
+
+	return nil
+}
+
+// pickerReply asks which of the candidates was meant. Only the member who
+// asked can answer, see `handlePick`.
+func pickerReply(l localized, msg *discordgo.Message, items []inat.SearchResultItem) *discordgo.MessageSend {
+	p := l.printer
+	options := make([]discordgo.SelectMenuOption, 0, len(items))
+
+	for _, item := range items {
+		t := item.Record.Taxon()
+		description := p.Sprintf(
+			"%s, %d observations",
+			cases.Title(language.English, cases.Compact).String(t.Rank),
+			t.ObservationCount,
+		)
+
+		options = append(options, discordgo.SelectMenuOption{
+			Label:       truncate(t.DisplayName(), maxOptionLength),
+			Description: truncate(description, maxOptionLength),
+			Value:       strconv.FormatInt(t.ID, 10),
+		})
+	}
+
+	return &discordgo.MessageSend{
+		Content: "There are a few matches for that, which one did you mean?",
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.SelectMenu{
+						MenuType:    discordgo.StringSelectMenu,
+						CustomID:    mod.ComponentID(moduleName, "pick", msg.Author.ID),
+						Placeholder: "Pick a taxon",
+						Options:     options,
+					},
+				},
+			},
+		},
+	}
+}
+
+// handlePick replaces the picker with the chosen taxon's embed
+func (m *Module) handlePick(d *discordgo.Session, i *discordgo.InteractionCreate, requesterID string) {
+	if user := mod.InteractionUser(i); user == nil || user.ID != requesterID {
+		mod.RespondEphemeral(d, i, fmt.Sprintf("Only <@%s> can pick, try looking it up yourself!", requesterID))
+		return
+	}
+
+	values := i.MessageComponentData().Values
+
+	if len(values) <= 0 {
+		mod.RespondEphemeral(d, i, "Pick one of the taxa.")
+		return
+	}
+
+	id, err := strconv.ParseInt(values[0], 10, 64)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "Sorry, that isn't one of the matches.")
+		return
+	}
+
+	// fetching the taxon and its local count can take longer than discord
+	// waits for a response
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to taxon pick", "err", err)
+		return
+	}
+
+	go func() {
+		// guilds that aren't configured get the defaults
+		config, _ := m.guildConfig(i.GuildID)
+		l := m.localized(config)
+		taxon, err := l.api.FetchTaxon(id)
+
+		if err != nil {
+			m.logger.Error("error fetching taxon", "taxon", id, "err", err)
+
+			// the picker is left as it is, so they can try again
+			_, err = d.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{
+				Content: "Sorry, something went wrong, please try again later.",
+				Flags:   discordgo.MessageFlagsEphemeral,
+			})
+
+			if err != nil {
+				m.logger.Error("error responding to taxon pick", "err", err)
+			}
+
+			return
+		}
+
+		content := ""
+		_, err = d.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &content,
+			Embeds:     &[]*discordgo.MessageEmbed{l.taxonEmbed(taxon)},
+			Components: &[]discordgo.MessageComponent{},
+		})
+
+		if err != nil {
+			m.logger.Error("error responding to taxon pick", "err", err)
+		}
+	}()
+}
+
+func truncate(s string, length int) string {
+	if r := []rune(s); len(r) > length {
+		return string(r[:length])
+	}
+
+	return s
+}
@@ -0,0 +1,259 @@
+package inatlookup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	deleteEmoji = "🗑"
+	// replies are remembered in memory for the most recent messages, and in
+	// the database for as long as they can still be edited or removed
+	maxCachedReplies = 500
+	replyRetention   = 7 * 24 * time.Hour
+)
+
+// replyCache remembers the replies to the most recent messages, so edits and
+// deletions don't all have to go to the database
+type replyCache struct {
+	entries map[string]store.LookupReply
+	order   []string
+	size    int
+	lock    sync.Mutex
+}
+
+func newReplyCache(size int) *replyCache {
+	return &replyCache{entries: make(map[string]store.LookupReply), size: size}
+}
+
+func (c *replyCache) get(messageID string) (store.LookupReply, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r, ok := c.entries[messageID]
+	return r, ok
+}
+
+func (c *replyCache) put(r store.LookupReply) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries[r.MessageID]; !ok {
+		c.order = append(c.order, r.MessageID)
+	}
+
+	c.entries[r.MessageID] = r
+
+	for len(c.order) > c.size {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *replyCache) remove(messageID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, messageID)
+	c.order = slices.DeleteFunc(c.order, func(id string) bool { return id == messageID })
+}
+
+func textReply(content string) *discordgo.MessageSend {
+	return &discordgo.MessageSend{Content: content}
+}
+
+func embedReply(embed *discordgo.MessageEmbed) *discordgo.MessageSend {
+	return &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}}
+}
+
+// reply sends the reply to a message, and remembers it so it can be kept in
+// sync with the message
+func (m *Module) reply(discord *discordgo.Session, msg *discordgo.Message, send *discordgo.MessageSend) {
+	sent, err := discord.ChannelMessageSendComplex(msg.ChannelID, send)
+
+	if err != nil {
+		m.logger.Error("error sending reply", "channel", msg.ChannelID, "err", err)
+		return
+	}
+
+	m.saveReply(store.LookupReply{
+		MessageID: msg.ID,
+		ChannelID: msg.ChannelID,
+		ReplyID:   sent.ID,
+		AuthorID:  msg.Author.ID,
+		Content:   msg.Content,
+	})
+}
+
+func (m *Module) saveReply(r store.LookupReply) {
+	err := m.db.SaveLookupReply(context.Background(), store.SaveLookupReplyParams{
+		MessageID: r.MessageID,
+		ChannelID: r.ChannelID,
+		ReplyID:   r.ReplyID,
+		AuthorID:  r.AuthorID,
+		Content:   r.Content,
+	})
+
+	if err != nil {
+		m.logger.Warn("error saving reply", "message", r.MessageID, "err", err)
+	}
+
+	m.replies.put(r)
+}
+
+func (m *Module) findReply(messageID string) (store.LookupReply, bool) {
+	if r, ok := m.replies.get(messageID); ok {
+		return r, true
+	}
+
+	r, err := m.db.FindLookupReply(context.Background(), messageID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return store.LookupReply{}, false
+	}
+
+	if err != nil {
+		m.logger.Warn("error fetching reply", "message", messageID, "err", err)
+		return store.LookupReply{}, false
+	}
+
+	m.replies.put(r)
+	return r, true
+}
+
+// syncReply updates the reply to an edited message, or removes it if the
+// message doesn't need one anymore
+func (m *Module) syncReply(discord *discordgo.Session, config GuildConfig, msg *discordgo.Message) {
+	// updates without content are discord adding link previews and the like
+	if msg.Content == "" {
+		return
+	}
+
+	r, ok := m.findReply(msg.ID)
+
+	if !ok || r.Content == msg.Content {
+		return
+	}
+
+	updated := *msg
+
+	if updated.Author == nil {
+		updated.Author = &discordgo.User{ID: r.AuthorID}
+	}
+
+	send, unfurled := m.respond(config, &updated)
+
+	if send == nil {
+		m.deleteReply(discord, r)
+		return
+	}
+
+	embeds := send.Embeds
+	components := send.Components
+
+	if embeds == nil {
+		embeds = []*discordgo.MessageEmbed{}
+	}
+
+	if components == nil {
+		components = []discordgo.MessageComponent{}
+	}
+
+	_, err := discord.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         r.ReplyID,
+		Channel:    r.ChannelID,
+		Content:    &send.Content,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+
+	if err != nil {
+		m.logger.Error("error editing reply", "message", r.MessageID, "reply", r.ReplyID, "err", err)
+		return
+	}
+
+	r.Content = msg.Content
+	m.saveReply(r)
+
+	if unfurled {
+		m.suppressPreviews(discord, &updated)
+	}
+}
+
+func (m *Module) deleteReply(discord *discordgo.Session, r store.LookupReply) {
+	err := discord.ChannelMessageDelete(r.ChannelID, r.ReplyID)
+
+	// the reply might have been deleted already
+	if err != nil && !isNotFound(err) {
+		m.logger.Error("error deleting reply", "message", r.MessageID, "reply", r.ReplyID, "err", err)
+		return
+	}
+
+	if err := m.db.DeleteLookupReply(context.Background(), r.MessageID); err != nil {
+		m.logger.Warn("error deleting reply record", "message", r.MessageID, "err", err)
+	}
+
+	m.replies.remove(r.MessageID)
+}
+
+// handleDeleteReaction lets the member who asked remove the reply by reacting
+// to it with 🗑️
+func (m *Module) handleDeleteReaction(discord *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	r, err := m.db.FindLookupReplyByReply(context.Background(), reaction.MessageID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return
+	}
+
+	if err != nil {
+		m.logger.Warn("error fetching reply", "reply", reaction.MessageID, "err", err)
+		return
+	}
+
+	if reaction.UserID != r.AuthorID {
+		return
+	}
+
+	m.deleteReply(discord, r)
+}
+
+// schedulePruning removes replies that are too old to be edited or deleted,
+// once a day
+func (m *Module) schedulePruning(ctx context.Context) {
+	err := m.scheduler.Register(ctx, mod.Job{
+		Name:     mod.JobName(moduleName, "prune"),
+		Module:   moduleName,
+		Schedule: "@daily",
+		CatchUp:  mod.CatchUpOnce,
+		Run:      m.pruneReplies,
+	})
+
+	if err != nil {
+		m.logger.Error("error scheduling reply pruning", "err", err)
+	}
+}
+
+func (m *Module) pruneReplies(ctx context.Context) error {
+	if err := m.db.DeleteOldLookupReplies(ctx, time.Now().UTC().Add(-replyRetention)); err != nil {
+		return fmt.Errorf("error pruning old replies: %w", err)
+	}
+
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var restErr *discordgo.RESTError
+
+	return errors.As(err, &restErr) &&
+		restErr.Response != nil &&
+		restErr.Response.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,163 @@
+package inatlookup
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	inatLinkRe = regexp.MustCompile(
+		`https?://(?:www\.)?inaturalist\.org/(observations|taxa|people|users|projects)/([\w-]+)`,
+	)
+	taxonSlugRe = regexp.MustCompile(`^(\d+)`)
+	// how many links in a message are unfurled
+	maxUnfurls = 3
+)
+
+type inatLink struct {
+	kind string
+	id   string
+}
+
+// findLinks returns the distinct iNaturalist links in a message
+func findLinks(content string) []inatLink {
+	var links []inatLink
+
+	for _, matches := range inatLinkRe.FindAllStringSubmatch(content, -1) {
+		link := inatLink{kind: matches[1], id: matches[2]}
+
+		switch link.kind {
+		case "users":
+			link.kind = "people"
+		case "taxa":
+			// taxon links usually have the name after the id, like
+			// `/taxa/47219-Apis-mellifera`
+			id := taxonSlugRe.FindString(link.id)
+
+			if id == "" {
+				continue
+			}
+
+			link.id = id
+		case "observations":
+			if _, err := strconv.ParseInt(link.id, 10, 64); err != nil {
+				continue
+			}
+		}
+
+		if !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+
+		if len(links) >= maxUnfurls {
+			break
+		}
+	}
+
+	return links
+}
+
+// unfurl answers iNaturalist links with embeds, which say a lot more than
+// discord's own previews
+func (m *Module) unfurl(l localized, msg *discordgo.Message) *discordgo.MessageSend {
+	links := findLinks(msg.Content)
+
+	if len(links) <= 0 {
+		return nil
+	}
+
+	embeds := make([]*discordgo.MessageEmbed, 0, len(links))
+
+	for _, link := range links {
+		embed, err := l.linkEmbed(link)
+
+		if err != nil {
+			m.logger.Warn("error unfurling link", "kind", link.kind, "id", link.id, "err", err)
+			continue
+		}
+
+		embeds = append(embeds, embed)
+	}
+
+	if len(embeds) <= 0 {
+		return nil
+	}
+
+	return &discordgo.MessageSend{Embeds: embeds}
+}
+
+// suppressPreviews hides discord's previews of the links in a message, if the
+// bot is allowed to
+func (m *Module) suppressPreviews(discord *discordgo.Session, msg *discordgo.Message) {
+	perms, err := discord.State.UserChannelPermissions(discord.State.User.ID, msg.ChannelID)
+
+	if err != nil || perms&discordgo.PermissionManageMessages == 0 {
+		return
+	}
+
+	_, err = discord.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:      msg.ID,
+		Channel: msg.ChannelID,
+		Flags:   discordgo.MessageFlagsSuppressEmbeds,
+	})
+
+	if err != nil {
+		m.logger.Warn("error suppressing link previews", "message", msg.ID, "err", err)
+	}
+}
+
+func (l localized) linkEmbed(link inatLink) (*discordgo.MessageEmbed, error) {
+	switch link.kind {
+	case "observations":
+		r, err := l.api.FetchObservations(url.Values{"id": {link.id}})
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(r.Results) <= 0 {
+			return nil, fmt.Errorf("observation %s not found", link.id)
+		}
+
+		return l.observationEmbed(r.Results[0]), nil
+	case "taxa":
+		id, err := strconv.ParseInt(link.id, 10, 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		taxon, err := l.api.FetchTaxon(id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return l.taxonEmbed(taxon), nil
+	case "people":
+		u, err := l.api.FetchUser(link.id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return l.userEmbed(u), nil
+	case "projects":
+		// project links can have the id instead of the slug, the api takes
+		// either
+		project, err := l.api.FetchProjectBySlug(link.id)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return l.projectEmbed(project)
+	}
+
+	return nil, fmt.Errorf("unknown link kind %s", link.kind)
+}
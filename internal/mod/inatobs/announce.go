@@ -0,0 +1,282 @@
+package inatobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+const (
+	// modeShowcase posts a random unseen observation on each run
+	modeShowcase = "showcase"
+	// modeAnnounce posts every observation added since the last run
+	modeAnnounce = "announce"
+)
+
+var (
+	modes                  = []string{modeShowcase, modeAnnounce}
+	announceKind           = moduleName + ":announce"
+	defaultDigestThreshold = int64(3)
+	maxDigestItems         = 20
+)
+
+func (c ChannelConfig) mode() string {
+	if c.Mode == "" {
+		return modeShowcase
+	}
+
+	return c.Mode
+}
+
+func (c ChannelConfig) digestThreshold() int64 {
+	if c.DigestThreshold <= 0 {
+		return defaultDigestThreshold
+	}
+
+	return c.DigestThreshold
+}
+
+// Announce posts the observations that have been added to a feed's project
+// since the last time it ran. The newest observation announced is stored as
+// a high-water mark. The first run only sets the mark, so a new feed doesn't
+// announce the project's entire history.
+func (m *Module) Announce(ctx context.Context, channelID, feed string) error {
+	options, err := m.feedOptions(channelID, feed)
+
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if quiet, err := mod.InQuietHours(options.QuietHours, options.Timezone, now); err != nil {
+		return err
+	} else if quiet {
+		// the mark is left alone, so everything that comes in during quiet
+		// hours is announced once they are over
+		m.logger.Info("quiet hours, skipping announcements", "channel", channelID, "feed", options.FeedName())
+		return nil
+	}
+
+	mark, err := m.db.FindAnnouncementMark(ctx, store.FindAnnouncementMarkParams{
+		ChannelID: channelID,
+		Feed:      options.FeedName(),
+	})
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error fetching announcement mark: %w", err)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || mark.ProjectID != options.ProjectID {
+		return m.resetAnnouncementMark(ctx, options)
+	}
+
+	observations, lastID, err := m.fetchNewObservations(options, mark.LastID)
+
+	if err != nil {
+		return err
+	}
+
+	if lastID == mark.LastID {
+		return nil
+	}
+
+	m.logger.Info(
+		"announcing new observations",
+		"channel",
+		channelID,
+		"feed",
+		options.FeedName(),
+		"count",
+		len(observations),
+	)
+
+	if int64(len(observations)) > options.digestThreshold() {
+		err = m.enqueueDigest(ctx, options, observations)
+	} else {
+		for _, o := range observations {
+			key := postDedupeKey(announceKind, options, o)
+
+			if _, err = m.enqueueObservation(ctx, options, o, announceKind, key); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return m.saveAnnouncementMark(ctx, options, lastID)
+}
+
+// fetchNewObservations returns the observations above `lastID` that pass the
+// feed's filters, and the id of the newest observation that was looked at
+func (m *Module) fetchNewObservations(
+	options ChannelConfig,
+	lastID int64,
+) ([]inat.Observation, int64, error) {
+	var observations []inat.Observation
+
+	for range syncMaxPages {
+		params := options.Filters.Params()
+		params.Set("project_id", strconv.FormatInt(options.ProjectID, 10))
+		params.Set("id_above", strconv.FormatInt(lastID, 10))
+		params.Set("order", "asc")
+		params.Set("order_by", "id")
+		params.Set("per_page", strconv.Itoa(syncPageSize))
+
+		r, err := m.api.FetchObservations(params)
+
+		if err != nil {
+			return nil, lastID, fmt.Errorf("error fetching new observations: %w", err)
+		}
+
+		for _, o := range r.Results {
+			lastID = max(lastID, o.ID)
+
+			if options.Filters.MatchObservation(o) {
+				observations = append(observations, o)
+			}
+		}
+
+		if len(r.Results) < syncPageSize {
+			break
+		}
+
+		time.Sleep(syncRequestDelay)
+	}
+
+	return observations, lastID, nil
+}
+
+func (m *Module) resetAnnouncementMark(ctx context.Context, options ChannelConfig) error {
+	r, err := m.api.FetchObservations(url.Values{
+		"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+		"order":      {"desc"},
+		"order_by":   {"id"},
+		"per_page":   {"1"},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error fetching newest observation: %w", err)
+	}
+
+	var lastID int64
+
+	if len(r.Results) > 0 {
+		lastID = r.Results[0].ID
+	}
+
+	m.logger.Info(
+		"starting announcements",
+		"channel",
+		options.ID,
+		"feed",
+		options.FeedName(),
+		"last_id",
+		lastID,
+	)
+
+	return m.saveAnnouncementMark(ctx, options, lastID)
+}
+
+func (m *Module) saveAnnouncementMark(ctx context.Context, options ChannelConfig, lastID int64) error {
+	err := m.db.SaveAnnouncementMark(ctx, store.SaveAnnouncementMarkParams{
+		ChannelID: options.ID,
+		Feed:      options.FeedName(),
+		ProjectID: options.ProjectID,
+		LastID:    lastID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving announcement mark: %w", err)
+	}
+
+	return nil
+}
+
+// enqueueDigest queues a single post that lists several new observations
+func (m *Module) enqueueDigest(
+	ctx context.Context,
+	options ChannelConfig,
+	observations []inat.Observation,
+) error {
+	lines := make([]string, 0, maxDigestItems+1)
+
+	for i, o := range observations {
+		if i >= maxDigestItems {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(observations)-maxDigestItems))
+			break
+		}
+
+		taxonName, commonName := o.TaxonNames()
+		lines = append(lines, fmt.Sprintf(
+			"[%s (%s)](https://inaturalist.org/observations/%d) by %s",
+			taxonName,
+			commonName,
+			o.ID,
+			o.User.DisplayName(),
+		))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		URL: fmt.Sprintf(
+			"https://inaturalist.org/observations?project_id=%d&id_above=%d",
+			options.ProjectID,
+			observations[0].ID-1,
+		),
+		Title:       fmt.Sprintf("%d new observations!", len(observations)),
+		Description: strings.Join(lines, "\n"),
+		Color:       embedColor,
+		Fields: []*discordgo.MessageEmbedField{{
+			Name:  "Our community iNaturalist Project",
+			Value: fmt.Sprintf("https://inaturalist.org/projects/%d", options.ProjectID),
+		}},
+	}
+
+	for _, o := range observations {
+		photos := allowedPhotos(o, options.Photos)
+
+		if len(photos) > 0 {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: photos[0].Medium()}
+
+			if credits := photoCredits(photos[:1]); credits != "" {
+				embed.Footer = &discordgo.MessageEmbedFooter{Text: credits}
+			}
+
+			break
+		}
+	}
+
+	_, err := m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      announceKind,
+		ChannelID: options.ID,
+		DedupeKey: fmt.Sprintf(
+			"%s:%s:%d-%d",
+			announceKind,
+			configKey(options.ID, options.FeedName()),
+			observations[0].ID,
+			observations[len(observations)-1].ID,
+		),
+		Message: mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing digest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package inatobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	anotherCooldown = 10 * time.Minute
+	// buttons on posts older than this stop working
+	componentStateRetention = 30 * 24 * time.Hour
+)
+
+// postState is saved for the buttons on each post
+type postState struct {
+	ChannelID     string `json:"channel_id"`
+	Feed          string `json:"feed"`
+	ProjectID     int64  `json:"project_id"`
+	ObservationID int64  `json:"observation_id"`
+}
+
+func postComponents(stateID string, o inat.Observation) []discordgo.ActionsRow {
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Another one",
+			Style:    discordgo.PrimaryButton,
+			CustomID: mod.ComponentID(moduleName, "another", stateID),
+		},
+	}
+
+	if len(o.Photos) > 0 {
+		buttons = append(buttons, discordgo.Button{
+			Label:    "Show all photos",
+			Style:    discordgo.SecondaryButton,
+			CustomID: mod.ComponentID(moduleName, "photos", stateID),
+		})
+	}
+
+	buttons = append(buttons, discordgo.Button{
+		Label: "Identify",
+		Style: discordgo.LinkButton,
+		URL: fmt.Sprintf(
+			"https://www.inaturalist.org/observations/identify?id=%d&reviewed=any&quality_grade=casual,needs_id,research",
+			o.ID,
+		),
+	})
+
+	return []discordgo.ActionsRow{{Components: buttons}}
+}
+
+func (m *Module) handleComponent(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	module, action, args, ok := mod.ParseComponentID(i.MessageComponentData().CustomID)
+
+	if !ok || module != moduleName || len(args) <= 0 {
+		return
+	}
+
+	ctx := context.Background()
+	state, err := mod.FetchComponentState[postState](ctx, m.db, args[0])
+
+	if err != nil {
+		m.logger.Error("error fetching post state", "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, I don't remember that post anymore.")
+		return
+	}
+
+	switch action {
+	case "another":
+		m.handleAnother(ctx, d, i, state)
+	case "photos":
+		m.handlePhotos(ctx, d, i, state, args[0], 0, false)
+	case "page":
+		if len(args) < 2 {
+			return
+		}
+
+		page, err := strconv.Atoi(args[1])
+
+		if err != nil {
+			return
+		}
+
+		m.handlePhotos(ctx, d, i, state, args[0], page, true)
+	}
+}
+
+// handleAnother posts another observation to the feed, as long as nobody
+// has asked for one recently
+func (m *Module) handleAnother(
+	ctx context.Context,
+	d *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	state postState,
+) {
+	options, err := m.feedOptions(state.ChannelID, state.Feed)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "This feed isn't around anymore.")
+		return
+	}
+
+	cooldownKey := fmt.Sprintf("%s:another:%s", moduleName, configKey(options.ID, options.FeedName()))
+	remaining, err := mod.TakeCooldown(ctx, m.db, cooldownKey, anotherCooldown)
+
+	if err != nil {
+		m.logger.Error("error checking cooldown", "err", err)
+		mod.RespondEphemeral(d, i, "Something went wrong, try again later.")
+		return
+	}
+
+	if remaining > 0 {
+		mod.RespondEphemeral(
+			d,
+			i,
+			fmt.Sprintf("Easy there! Try again in %s.", remaining.Round(time.Second)),
+		)
+		return
+	}
+
+	// picking and queueing the observation can take longer than discord
+	// waits for a response
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to another observation request", "err", err)
+		return
+	}
+
+	go func() {
+		content := "Another observation is on its way!"
+
+		if err := m.Post(d, options.ID, options.FeedName(), PostRequest{}); err != nil {
+			m.logger.Error("error posting observation", "err", err)
+			content = loadInatErrorMessage(err)
+
+			// nothing was posted, so the next member can try right away
+			if err := mod.ReleaseCooldown(context.Background(), m.db, cooldownKey); err != nil {
+				m.logger.Error("error releasing cooldown", "err", err)
+			}
+		}
+
+		_, err := d.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content})
+
+		if err != nil {
+			m.logger.Error("error responding to another observation request", "err", err)
+		}
+	}()
+}
+
+// handlePhotos shows one of an observation's photos, with buttons to page
+// through the rest. The gallery is only shown to the member that asked for
+// it.
+func (m *Module) handlePhotos(
+	ctx context.Context,
+	d *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	state postState,
+	stateID string,
+	page int,
+	update bool,
+) {
+	o, err := m.findObservation(ctx, state.ProjectID, state.ObservationID)
+
+	if err != nil {
+		m.logger.Error("error fetching observation", "id", state.ObservationID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, I couldn't load the photos for that observation.")
+		return
+	}
+
+	var photosConfig Photos
+
+	if options, err := m.feedOptions(state.ChannelID, state.Feed); err == nil {
+		photosConfig = options.Photos
+	}
+
+	photos := allowedPhotos(o, photosConfig)
+
+	if len(photos) <= 0 {
+		mod.RespondEphemeral(d, i, "The photos for this observation can't be shared here, take a look on iNaturalist!")
+		return
+	}
+
+	// the photos may have changed since the page buttons were made
+	page = min(max(page, 0), len(photos)-1)
+	photo := photos[page]
+	footer := fmt.Sprintf("Photo %d of %d", page+1, len(photos))
+
+	if photo.Attribution != "" {
+		footer = fmt.Sprintf("%s · %s", footer, photo.Attribution)
+	}
+
+	taxonName, commonName := o.TaxonNames()
+	data := &discordgo.InteractionResponseData{
+		Flags: discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{{
+			URL:    fmt.Sprintf("https://inaturalist.org/observations/%d", o.ID),
+			Title:  fmt.Sprintf("%s (%s)", taxonName, commonName),
+			Color:  embedColor,
+			Image:  &discordgo.MessageEmbedImage{URL: photo.Medium()},
+			Footer: &discordgo.MessageEmbedFooter{Text: footer},
+		}},
+		Components: []discordgo.MessageComponent{},
+	}
+
+	if len(photos) > 1 {
+		data.Components = []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Previous",
+					Style:    discordgo.SecondaryButton,
+					Disabled: page <= 0,
+					CustomID: mod.ComponentID(moduleName, "page", stateID, strconv.Itoa(page-1)),
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					Disabled: page >= len(photos)-1,
+					CustomID: mod.ComponentID(moduleName, "page", stateID, strconv.Itoa(page+1)),
+				},
+			}},
+		}
+	}
+
+	responseType := discordgo.InteractionResponseChannelMessageWithSource
+
+	if update {
+		responseType = discordgo.InteractionResponseUpdateMessage
+	}
+
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: responseType,
+		Data: data,
+	})
+
+	if err != nil {
+		m.logger.Error("error showing photos", "err", err)
+	}
+}
+
+// findObservation returns an observation from the local mirror, falling back
+// to the api if it isn't there
+func (m *Module) findObservation(
+	ctx context.Context,
+	projectID int64,
+	id int64,
+) (inat.Observation, error) {
+	row, err := m.db.FindProjectObservation(ctx, store.FindProjectObservationParams{
+		ProjectID: projectID,
+		ID:        id,
+	})
+
+	if err == nil {
+		return observationFromRow(row)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return inat.Observation{}, err
+	}
+
+	return m.fetchProjectObservation(projectID, id)
+}
+
+// fetchProjectObservation fetches an observation from iNaturalist.
+// Observations that aren't in the project can't be posted to its feeds, so
+// they aren't found.
+func (m *Module) fetchProjectObservation(projectID, id int64) (inat.Observation, error) {
+	r, err := m.api.FetchObservations(url.Values{
+		"id":         {strconv.FormatInt(id, 10)},
+		"project_id": {strconv.FormatInt(projectID, 10)},
+	})
+
+	if err != nil {
+		return inat.Observation{}, err
+	}
+
+	if len(r.Results) <= 0 {
+		return inat.Observation{}, fmt.Errorf("observation %d: %w", id, errObservationNotFound)
+	}
+
+	return r.Results[0], nil
+}
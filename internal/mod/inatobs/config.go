@@ -1,44 +1,205 @@
 package inatobs
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/synic/glap"
 
+	"github.com/synic/buggins/internal/inat"
 	"github.com/synic/buggins/internal/mod"
 )
 
+var (
+	defaultFeed = "default"
+)
+
+// ChannelConfig configures a feed of observations posted to a channel. A
+// channel can have several feeds, each with its own schedule and its own
+// record of which observations have been posted.
 type ChannelConfig struct {
-	ID          string `json:"id"`
-	CronPattern string `json:"cron_pattern"`
-	ProjectID   int64  `json:"inat_project_id"`
-	PageSize    int    `json:"page_size"`
+	ID              string  `json:"id"`
+	Feed            string  `json:"feed"`
+	Mode            string  `json:"mode"`
+	CronPattern     string  `json:"cron_pattern"`
+	Timezone        string  `json:"timezone"`
+	CatchUp         string  `json:"catch_up"`
+	QuietHours      string  `json:"quiet_hours"`
+	ProjectID       int64   `json:"inat_project_id"`
+	DigestThreshold int64   `json:"digest_threshold"`
+	Strategy        string  `json:"strategy"`
+	Filters         Filters `json:"filters"`
+	Photos          Photos  `json:"photos"`
+}
+
+// FeedName returns the name of the feed, configurations from before feeds
+// were added don't have one
+func (c ChannelConfig) FeedName() string {
+	if c.Feed == "" {
+		return defaultFeed
+	}
+
+	return c.Feed
+}
+
+// configKey returns the module configuration key for a feed. The default
+// feed is keyed by the channel id alone, so configurations from before feeds
+// were added still line up.
+func configKey(channelID, feed string) string {
+	if feed == "" || feed == defaultFeed {
+		return channelID
+	}
+
+	return fmt.Sprintf("%s/%s", channelID, feed)
+}
+
+func validateIDs(v string) error {
+	_, err := parseIDs([]string{v})
+	return err
+}
+
+func validateDate(v string) error {
+	if _, err := time.Parse(time.DateOnly, v); err != nil {
+		return fmt.Errorf("invalid date '%s', expected YYYY-MM-DD", v)
+	}
+
+	return nil
 }
 
 func ConfigCommandOptions() mod.ConfigCommandOptions {
 	args := []*glap.Arg{
 		glap.NewArg("channel-id").Short('c').Required(true).Help("Channel CHANNEL_ID"),
+		glap.NewArg("feed").Short('f').Default(defaultFeed).Help("Feed name FEED"),
 		glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID"),
-		glap.NewArg("schedule-pattern").Default("0 * * * *").Help("Schedule cron pattern PATTERN"),
-		glap.NewArg("page-size").Default("10").Help("Number of pages to fetch from iNaturalist SIZE"),
+		glap.NewArg("mode").
+			Short('m').
+			Default(modeShowcase).
+			PossibleValues(modes...).
+			Help("Post a random observation each run, or announce new ones MODE"),
+		glap.NewArg("schedule-pattern").
+			Default("0 * * * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("Schedule cron pattern PATTERN"),
+		glap.NewArg("timezone").
+			Validator(func(v string) error { return mod.ValidateSchedule("@daily", v) }).
+			Help("Timezone the schedule runs in, defaults to UTC TIMEZONE"),
+		glap.NewArg("catch-up").
+			Default(string(mod.CatchUpOnce)).
+			PossibleValues(mod.CatchUpPolicies...).
+			Help("What to do about posts missed while the bot was down POLICY"),
+		glap.NewArg("quiet-hours").
+			Validator(mod.ValidateQuietHours).
+			Help("Don't announce anything between these times, for example 22:00-07:00 HOURS"),
+		glap.NewArg("digest-threshold").
+			Default("3").
+			Help("Announce more than COUNT new observations in a single digest"),
+		glap.NewArg("strategy").
+			Short('s').
+			Default(defaultStrategy).
+			PossibleValues(StrategyNames()...).
+			Help("Observation selection strategy STRATEGY"),
+		glap.NewArg("taxon-id").
+			Action(glap.Append).
+			Validator(validateIDs).
+			Help("Only show observations of these taxa and their descendants TAXON_IDS"),
+		glap.NewArg("iconic-taxon").
+			Action(glap.Append).
+			PossibleValues(iconicTaxa...).
+			Help("Only show observations of these iconic taxa ICONIC_TAXA"),
+		glap.NewArg("quality-grade").
+			Action(glap.Append).
+			PossibleValues(qualityGrades...).
+			Help("Only show observations with these quality grades GRADES"),
+		glap.NewArg("place-id").
+			Action(glap.Append).
+			Validator(validateIDs).
+			Help("Only show observations from these places PLACE_IDS"),
+		glap.NewArg("observed-after").
+			Validator(validateDate).
+			Help("Only show observations made on or after DATE (YYYY-MM-DD)"),
+		glap.NewArg("observed-before").
+			Validator(validateDate).
+			Help("Only show observations made on or before DATE (YYYY-MM-DD)"),
+		glap.NewArg("has-photos").
+			Default("true").
+			PossibleValues("true", "false", "any").
+			Help("Whether observations must have photos PHOTOS"),
+		glap.NewArg("min-faves").Default("0").Help("Only show observations with at least COUNT faves"),
+		glap.NewArg("exclude-user").
+			Action(glap.Append).
+			Help("Never show observations from these iNaturalist users LOGINS"),
+		glap.NewArg("photo-mode").
+			Default(photoModeUpload).
+			PossibleValues(photoModes...).
+			Help("Whether to upload photos or link to them on iNaturalist MODE"),
+		glap.NewArg("photo-license").
+			Action(glap.Append).
+			PossibleValues(inat.PhotoLicenses...).
+			Help("Only post photos with these licenses, defaults to any creative commons license LICENSES"),
 	}
 
 	return mod.ConfigCommandOptions{
 		Args:       args,
-		KeyArg:     "channel-id",
+		KeyArgs:    []string{"channel-id", "feed"},
 		ModuleName: moduleName,
 		GetKey: func(m *glap.Matches) string {
-			v, _ := m.GetString("channel-id")
-			return v
+			channelID, _ := m.GetString("channel-id")
+			feed, _ := m.GetString("feed")
+			return configKey(channelID, feed)
 		},
 		GetData: func(m *glap.Matches) any {
 			channelID, _ := m.GetString("channel-id")
+			feed, _ := m.GetString("feed")
 			projectID, _ := m.GetInt64("project-id")
-			pageSize, _ := m.GetInt("page-size")
+			mode, _ := m.GetString("mode")
+			quietHours, _ := m.GetString("quiet-hours")
+			digestThreshold, _ := m.GetInt64("digest-threshold")
 			cronPattern, _ := m.GetString("schedule-pattern")
+			timezone, _ := m.GetString("timezone")
+			catchUp, _ := m.GetString("catch-up")
+			strategy, _ := m.GetString("strategy")
+			taxonIDs, _ := m.GetStringSlice("taxon-id")
+			iconicTaxa, _ := m.GetStringSlice("iconic-taxon")
+			qualityGrades, _ := m.GetStringSlice("quality-grade")
+			placeIDs, _ := m.GetStringSlice("place-id")
+			observedAfter, _ := m.GetString("observed-after")
+			observedBefore, _ := m.GetString("observed-before")
+			photos, _ := m.GetString("has-photos")
+			minFaves, _ := m.GetInt64("min-faves")
+			excludedUsers, _ := m.GetStringSlice("exclude-user")
+			photoMode, _ := m.GetString("photo-mode")
+			licenses, _ := m.GetStringSlice("photo-license")
+
+			// ids have already been checked by the arg validators
+			parsedTaxonIDs, _ := parseIDs(taxonIDs)
+			parsedPlaceIDs, _ := parseIDs(placeIDs)
+
 			return ChannelConfig{
-				ID:          channelID,
-				ProjectID:   projectID,
-				PageSize:    pageSize,
-				CronPattern: cronPattern,
+				ID:              channelID,
+				Feed:            feed,
+				Mode:            mode,
+				ProjectID:       projectID,
+				CronPattern:     cronPattern,
+				Timezone:        timezone,
+				CatchUp:         catchUp,
+				QuietHours:      quietHours,
+				DigestThreshold: digestThreshold,
+				Strategy:        strategy,
+				Filters: Filters{
+					TaxonIDs:       parsedTaxonIDs,
+					IconicTaxa:     iconicTaxa,
+					QualityGrades:  qualityGrades,
+					PlaceIDs:       parsedPlaceIDs,
+					ObservedAfter:  observedAfter,
+					ObservedBefore: observedBefore,
+					Photos:         photos,
+					MinFaves:       minFaves,
+					ExcludedUsers:  excludedUsers,
+				},
+				Photos: Photos{
+					Mode:     photoMode,
+					Licenses: licenses,
+				},
 			}
 		},
 	}
@@ -0,0 +1,205 @@
+package inatobs
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+)
+
+const (
+	photoModeUpload = "upload"
+	photoModeLink   = "link"
+)
+
+var (
+	photoModes        = []string{photoModeUpload, photoModeLink}
+	maxUploadedPhotos = 5
+	// discord only shows up to four images from embeds that share a url
+	maxLinkedPhotos   = 4
+	embedColor        = 2123412
+	qualityGradeNames = map[string]string{
+		"research": "Research Grade",
+		"needs_id": "Needs ID",
+		"casual":   "Casual",
+	}
+)
+
+// Photos configures how a channel shows observation photos
+type Photos struct {
+	// Mode is either "upload", to re-upload photos to discord, or "link", to
+	// have discord show them from iNaturalist
+	Mode string `json:"mode,omitempty"`
+	// Licenses are the photo licenses that may be posted. Photos with all
+	// rights reserved are never posted.
+	Licenses []string `json:"licenses,omitempty"`
+}
+
+func (p Photos) mode() string {
+	if p.Mode == "" {
+		return photoModeUpload
+	}
+
+	return p.Mode
+}
+
+// Allowed reports whether a photo's license lets it be posted
+func (p Photos) Allowed(photo inat.Photo) bool {
+	return photo.Licensed(p.Licenses...)
+}
+
+// allowedPhotos returns the observation's photos that may be posted
+func allowedPhotos(o inat.Observation, config Photos) []inat.Photo {
+	return o.LicensedPhotos(config.Licenses...)
+}
+
+// observationMessage builds the post for an observation
+func observationMessage(o inat.Observation, options ChannelConfig) mod.OutboxMessage {
+	observationURL := fmt.Sprintf("https://inaturalist.org/observations/%d", o.ID)
+	taxonName, commonName := o.TaxonNames()
+	author := o.User.DisplayName()
+
+	if author != o.User.Username {
+		author = fmt.Sprintf("%s (%s)", author, o.User.Username)
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Taxon", Value: fmt.Sprintf("%s (%s)", taxonName, commonName)},
+	}
+
+	if o.ObservedOn != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Observed",
+			Value:  o.ObservedOn,
+			Inline: true,
+		})
+	}
+
+	if o.PlaceGuess != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Place",
+			Value:  o.PlaceGuess,
+			Inline: true,
+		})
+	}
+
+	if grade, ok := qualityGradeNames[o.QualityGrade]; ok {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Quality Grade",
+			Value:  grade,
+			Inline: true,
+		})
+	}
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Identifications",
+		Value:  fmt.Sprintf("%d", o.IdentificationsCount),
+		Inline: true,
+	})
+
+	photos := allowedPhotos(o, options.Photos)
+
+	if len(photos) <= 0 && len(o.Photos) > 0 {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:  "Photos",
+			Value: "The photos for this observation can't be shared here, take a look on iNaturalist!",
+		})
+	}
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:  "Our community iNaturalist Project",
+		Value: fmt.Sprintf("https://inaturalist.org/projects/%d", options.ProjectID),
+	})
+
+	embed := &discordgo.MessageEmbed{
+		URL:   observationURL,
+		Title: fmt.Sprintf("%s has spotted something new!", o.User.DisplayName()),
+		Author: &discordgo.MessageEmbedAuthor{
+			Name:    author,
+			URL:     fmt.Sprintf("https://inaturalist.org/people/%d", o.User.ID),
+			IconURL: o.User.UserIconURL,
+		},
+		Color:  embedColor,
+		Fields: fields,
+	}
+
+	message := mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}}
+
+	if options.Photos.mode() == photoModeLink {
+		if len(photos) > maxLinkedPhotos {
+			photos = photos[:maxLinkedPhotos]
+		}
+
+		// embeds that share a url are shown by discord as a single embed
+		// with a gallery of their images
+		for i, photo := range photos {
+			image := &discordgo.MessageEmbedImage{URL: photo.Medium()}
+
+			if i == 0 {
+				embed.Image = image
+				continue
+			}
+
+			message.Embeds = append(message.Embeds, &discordgo.MessageEmbed{
+				URL:   observationURL,
+				Image: image,
+			})
+		}
+	} else {
+		if len(photos) > maxUploadedPhotos {
+			photos = photos[:maxUploadedPhotos]
+		}
+
+		for i, photo := range photos {
+			name := photoFilename(o.ID, i+1, photo.Medium())
+			message.Files = append(message.Files, mod.OutboxFile{
+				URL:         photo.Medium(),
+				Name:        name,
+				ContentType: mime.TypeByExtension(path.Ext(name)),
+			})
+		}
+	}
+
+	if credits := photoCredits(photos); credits != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: credits}
+	}
+
+	return message
+}
+
+// photoCredits returns the attribution for each photographer whose photos
+// are posted
+func photoCredits(photos []inat.Photo) string {
+	var credits []string
+
+	for _, p := range photos {
+		if p.Attribution != "" && !slices.Contains(credits, p.Attribution) {
+			credits = append(credits, p.Attribution)
+		}
+	}
+
+	if len(credits) <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("Photos: %s", strings.Join(credits, "; "))
+}
+
+// photoFilename names an uploaded photo after the observation it belongs to,
+// keeping the extension of the original
+func photoFilename(observationID int64, n int, photoURL string) string {
+	ext := ".jpg"
+
+	if u, err := url.Parse(photoURL); err == nil && path.Ext(u.Path) != "" {
+		ext = strings.ToLower(path.Ext(u.Path))
+	}
+
+	return fmt.Sprintf("observation-%d-%d%s", observationID, n, ext)
+}
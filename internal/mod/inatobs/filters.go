@@ -0,0 +1,231 @@
+package inatobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	qualityGrades = []string{"research", "needs_id", "casual"}
+	iconicTaxa    = []string{
+		"Plantae", "Animalia", "Mollusca", "Reptilia", "Aves", "Amphibia",
+		"Actinopterygii", "Mammalia", "Insecta", "Arachnida", "Fungi",
+		"Protozoa", "Chromista",
+	}
+)
+
+// Filters narrows down which of a project's observations a channel will
+// show. Each field corresponds to an iNaturalist observation search
+// parameter, see `Params`.
+type Filters struct {
+	TaxonIDs       []int64  `json:"taxon_ids,omitempty"`
+	IconicTaxa     []string `json:"iconic_taxa,omitempty"`
+	QualityGrades  []string `json:"quality_grades,omitempty"`
+	PlaceIDs       []int64  `json:"place_ids,omitempty"`
+	ObservedAfter  string   `json:"observed_after,omitempty"`
+	ObservedBefore string   `json:"observed_before,omitempty"`
+	Photos         string   `json:"photos,omitempty"`
+	ExcludedUsers  []string `json:"excluded_users,omitempty"`
+	MinFaves       int64    `json:"min_faves,omitempty"`
+}
+
+// Params returns the filters as iNaturalist observation search parameters.
+// The api has no way to filter on the number of faves, so `MinFaves` must be
+// checked on the results.
+func (f Filters) Params() url.Values {
+	params := url.Values{}
+
+	if len(f.TaxonIDs) > 0 {
+		params.Set("taxon_id", joinIDs(f.TaxonIDs))
+	}
+
+	if len(f.IconicTaxa) > 0 {
+		params.Set("iconic_taxa", strings.Join(f.IconicTaxa, ","))
+	}
+
+	if len(f.QualityGrades) > 0 {
+		params.Set("quality_grade", strings.Join(f.QualityGrades, ","))
+	}
+
+	if len(f.PlaceIDs) > 0 {
+		params.Set("place_id", joinIDs(f.PlaceIDs))
+	}
+
+	if f.ObservedAfter != "" {
+		params.Set("d1", f.ObservedAfter)
+	}
+
+	if f.ObservedBefore != "" {
+		params.Set("d2", f.ObservedBefore)
+	}
+
+	switch f.photos() {
+	case "true", "false":
+		params.Set("photos", f.photos())
+	}
+
+	if len(f.ExcludedUsers) > 0 {
+		params.Set("not_user_id", strings.Join(f.ExcludedUsers, ","))
+	}
+
+	return params
+}
+
+// photos returns whether observations must have photos ("true"), must not
+// have photos ("false") or either ("any"). Posts are a photo showcase, so
+// observations without photos are skipped unless asked for.
+func (f Filters) photos() string {
+	if f.Photos == "" {
+		return "true"
+	}
+
+	return f.Photos
+}
+
+// Match reports whether an observation from the local mirror passes the
+// filters.
+func (f Filters) Match(c candidate) bool {
+	if len(f.TaxonIDs) > 0 && !slices.ContainsFunc(f.TaxonIDs, func(id int64) bool {
+		return containsID(c.TaxonAncestry, id)
+	}) {
+		return false
+	}
+
+	if len(f.IconicTaxa) > 0 && !slices.ContainsFunc(f.IconicTaxa, func(name string) bool {
+		return strings.EqualFold(name, c.IconicTaxon)
+	}) {
+		return false
+	}
+
+	if len(f.QualityGrades) > 0 && !slices.Contains(f.QualityGrades, c.QualityGrade) {
+		return false
+	}
+
+	if len(f.PlaceIDs) > 0 && !slices.ContainsFunc(f.PlaceIDs, func(id int64) bool {
+		return containsID(c.PlaceIds, id)
+	}) {
+		return false
+	}
+
+	// observed_on is stored as YYYY-MM-DD, so the dates compare as strings
+	if f.ObservedAfter != "" && (c.ObservedOn == "" || c.ObservedOn < f.ObservedAfter) {
+		return false
+	}
+
+	if f.ObservedBefore != "" && (c.ObservedOn == "" || c.ObservedOn > f.ObservedBefore) {
+		return false
+	}
+
+	switch f.photos() {
+	case "true":
+		if c.PhotoCount <= 0 {
+			return false
+		}
+	case "false":
+		if c.PhotoCount > 0 {
+			return false
+		}
+	}
+
+	if slices.ContainsFunc(f.ExcludedUsers, func(u string) bool {
+		return strings.EqualFold(u, c.UserLogin) || u == strconv.FormatInt(c.UserID, 10)
+	}) {
+		return false
+	}
+
+	return c.FavesCount >= f.MinFaves
+}
+
+// candidateParams returns the filters as parameters for selecting unseen
+// observations from the local mirror
+func (f Filters) candidateParams() store.FindUnseenObservationCandidatesParams {
+	return store.FindUnseenObservationCandidatesParams{
+		TaxonIds:       jsonList(f.TaxonIDs),
+		IconicTaxa:     jsonList(f.IconicTaxa),
+		QualityGrades:  jsonList(f.QualityGrades),
+		PlaceIds:       jsonList(f.PlaceIDs),
+		ExcludedUsers:  jsonList(f.ExcludedUsers),
+		ObservedAfter:  f.ObservedAfter,
+		ObservedBefore: f.ObservedBefore,
+		Photos:         f.photos(),
+		MinFaves:       f.MinFaves,
+	}
+}
+
+// MatchObservation reports whether an observation fetched from iNaturalist
+// passes the filters.
+func (f Filters) MatchObservation(o inat.Observation) bool {
+	return f.Match(candidate{
+		UserID:        o.User.ID,
+		UserLogin:     o.User.Username,
+		TaxonID:       o.Taxon.ID,
+		IconicTaxon:   o.Taxon.IconicTaxonName,
+		TaxonAncestry: taxonAncestry(o),
+		PlaceIds:      joinIDs(o.PlaceIDs),
+		QualityGrade:  o.QualityGrade,
+		FavesCount:    o.FavesCount,
+		PhotoCount:    int64(len(o.Photos)),
+		ObservedOn:    o.ObservedOn,
+	})
+}
+
+// taxonAncestry returns the ids of the observation's taxon and all of its
+// ancestors
+func taxonAncestry(o inat.Observation) string {
+	ids := slices.Clone(o.Taxon.AncestorIDs)
+
+	if o.Taxon.ID != 0 && !slices.Contains(ids, o.Taxon.ID) {
+		ids = append(ids, o.Taxon.ID)
+	}
+
+	return joinIDs(ids)
+}
+
+// jsonList encodes a filter list as a json array, empty lists included
+func jsonList[T any](items []T) string {
+	if len(items) == 0 {
+		return "[]"
+	}
+
+	data, _ := json.Marshal(items)
+	return string(data)
+}
+
+func joinIDs(ids []int64) string {
+	items := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		items = append(items, strconv.FormatInt(id, 10))
+	}
+
+	return strings.Join(items, ",")
+}
+
+func containsID(ids string, id int64) bool {
+	return slices.Contains(strings.Split(ids, ","), strconv.FormatInt(id, 10))
+}
+
+func parseIDs(items []string) ([]int64, error) {
+	ids := make([]int64, 0, len(items))
+
+	for _, item := range items {
+		for _, v := range strings.Split(item, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+
+			if err != nil {
+				return nil, fmt.Errorf("invalid id '%s'", v)
+			}
+
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
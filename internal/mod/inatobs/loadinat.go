@@ -0,0 +1,209 @@
+package inatobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+)
+
+// PostRequest narrows down which observation `Post` picks. The zero value
+// picks any unseen observation.
+type PostRequest struct {
+	// ObservationID posts a specific observation, whether or not it has been
+	// seen
+	ObservationID int64
+	// UserLogin only picks from observations by this iNaturalist user
+	UserLogin string
+	// TaxonID only picks observations of this taxon or its descendants
+	TaxonID int64
+	// RequestID identifies the request for a specific observation, so
+	// repeats of the same request only post it once
+	RequestID string
+}
+
+func (m *Module) pickObservation(options ChannelConfig, request PostRequest) (inat.Observation, error) {
+	if request.ObservationID != 0 {
+		o, err := m.findObservation(context.Background(), options.ProjectID, request.ObservationID)
+
+		if err != nil {
+			return inat.Observation{}, fmt.Errorf("error fetching observation: %w", err)
+		}
+
+		return o, nil
+	}
+
+	return m.findUnseenObservation(options, request)
+}
+
+func (m *Module) handleLoadInat(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	var (
+		feed    string
+		taxon   string
+		preview bool
+		request PostRequest
+	)
+
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "feed":
+			feed = option.StringValue()
+		case "observation":
+			request.ObservationID = option.IntValue()
+			request.RequestID = i.ID
+		case "user":
+			request.UserLogin = strings.TrimPrefix(strings.TrimSpace(option.StringValue()), "@")
+		case "taxon":
+			taxon = strings.TrimSpace(option.StringValue())
+		case "preview":
+			preview = option.BoolValue()
+		}
+	}
+
+	options, err := m.feedOptions(i.ChannelID, feed)
+
+	if err != nil {
+		content := "Wrong channel, bub."
+
+		if len(m.channelFeeds(i.ChannelID)) > 0 {
+			content = fmt.Sprintf("There's no feed named '%s' in this channel.", feed)
+		}
+
+		d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: content,
+			},
+		})
+		return
+	}
+
+	var flags discordgo.MessageFlags
+
+	if preview {
+		flags = discordgo.MessageFlagsEphemeral
+	}
+
+	// finding an observation can mean syncing the project or looking up the
+	// taxon, which takes longer than discord waits for a response
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to /loadinat", "err", err)
+		return
+	}
+
+	m.logger.Info(
+		"/loadinat called, loading observation to display",
+		"feed",
+		options.FeedName(),
+		"preview",
+		preview,
+	)
+
+	go func() {
+		edit := m.loadInat(d, options, taxon, request, preview)
+
+		if _, err := d.InteractionResponseEdit(i.Interaction, edit); err != nil {
+			m.logger.Error("error responding to /loadinat", "err", err)
+		}
+	}()
+}
+
+// loadInat posts or previews an observation for `/loadinat`, and returns the
+// response to show the moderator
+func (m *Module) loadInat(
+	d *discordgo.Session,
+	options ChannelConfig,
+	taxon string,
+	request PostRequest,
+	preview bool,
+) *discordgo.WebhookEdit {
+	reply := func(content string) *discordgo.WebhookEdit {
+		return &discordgo.WebhookEdit{Content: &content}
+	}
+
+	if taxon != "" {
+		t, err := mod.ResolveTaxon(m.api, taxon)
+
+		if err != nil {
+			m.logger.Error("error looking up taxon", "taxon", taxon, "err", err)
+			return reply(fmt.Sprintf("Sorry, I couldn't find a taxon named '%s'.", taxon))
+		}
+
+		request.TaxonID = t.ID
+	}
+
+	if !preview {
+		if err := m.Post(d, options.ID, options.FeedName(), request); err != nil {
+			m.logger.Error("error posting observation", "err", err)
+			return reply(loadInatErrorMessage(err))
+		}
+
+		return reply("Done, observation is loading and will be posted soon!")
+	}
+
+	o, err := m.pickObservation(options, request)
+
+	if err != nil {
+		m.logger.Error("error previewing observation", "err", err)
+		return reply(loadInatErrorMessage(err))
+	}
+
+	// files can't be attached to an edited response, so previews always link
+	// to the photos
+	options.Photos.Mode = photoModeLink
+	message := observationMessage(o, options)
+	content := "Here's what would be posted:"
+
+	return &discordgo.WebhookEdit{Content: &content, Embeds: &message.Embeds}
+}
+
+func (m *Module) handleLoadInatAutocomplete(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	var q string
+
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == "taxon" && option.Focused {
+			q = strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	choices, err := mod.TaxonChoices(m.api, q)
+
+	if err != nil {
+		m.logger.Error("error searching taxa", "q", q, "err", err)
+	}
+
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to taxon autocomplete", "err", err)
+	}
+}
+
+func loadInatErrorMessage(err error) string {
+	if errors.Is(err, errNoCandidates) {
+		return "There are no unseen observations that match."
+	}
+
+	if errors.Is(err, errObservationNotFound) {
+		return "That observation isn't part of this feed's project."
+	}
+
+	if errors.Is(err, errAlreadyQueued) {
+		return "That observation is already queued to be posted."
+	}
+
+	return "Sorry, something went wrong loading the observation."
+}
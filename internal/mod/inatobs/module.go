@@ -2,17 +2,16 @@ package inatobs
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
-	"maps"
 	"math/rand/v2"
-	"net/http"
 	"slices"
 	"sync"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/robfig/cron/v3"
 
 	"github.com/synic/buggins/internal/inat"
 	"github.com/synic/buggins/internal/mod"
@@ -21,33 +20,65 @@ import (
 
 var (
 	moduleName = "inatobs"
+
+	errAlreadyQueued       = errors.New("observation is already queued to be posted")
+	errObservationNotFound = errors.New("observation not found in the feed's project")
+
+	// how many picks are tried when the picked observations turn out to be
+	// gone from iNaturalist
+	maxSelectAttempts = 5
 )
 
 type Module struct {
 	api                     inat.Api
 	logger                  *slog.Logger
 	db                      *store.Queries
-	displayedObservers      map[string][]int64
+	displayedObservers      map[rotationKey][]int64
+	scheduler               *mod.Scheduler
+	outbox                  *mod.Outbox
 	config                  []ChannelConfig
-	crons                   []*cron.Cron
 	slashCommandsRegistered bool
 	configLock              sync.RWMutex
-	cronsLock               sync.Mutex
 	displayedObserversLock  sync.RWMutex
+	syncLock                sync.Mutex
+}
+
+// postMeta identifies the observation a queued post is for
+type postMeta struct {
+	ChannelID     string `json:"channel_id"`
+	Feed          string `json:"feed"`
+	ProjectID     int64  `json:"project_id"`
+	ObservationID int64  `json:"observation_id"`
+	UserID        int64  `json:"user_id"`
 }
 
-func New(db *store.Queries, logger *slog.Logger) (*Module, error) {
-	return &Module{
+func New(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (*Module, error) {
+	m := &Module{
 		api:                inat.New(),
 		db:                 db,
+		scheduler:          scheduler,
+		outbox:             outbox,
 		logger:             logger,
-		displayedObservers: make(map[string][]int64),
-		crons:              make([]*cron.Cron, 0),
-	}, nil
+		displayedObservers: make(map[rotationKey][]int64),
+	}
+
+	outbox.OnDelivered(moduleName, m.markObservationAsSeen)
+	outbox.OnDelivered(announceKind, m.markObservationAsSeen)
+	return m, nil
 }
 
-func Provider(db *store.Queries, logger *slog.Logger) (mod.ModuleProviderResult, error) {
-	module, err := New(db, logger.With("mod", moduleName))
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, outbox, logger.With("mod", moduleName))
 
 	if err != nil {
 		return mod.ModuleProviderResult{}, err
@@ -74,27 +105,112 @@ func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *stor
 		return err
 	}
 	m.SetConfig(config)
+
+	if err := m.loadDisplayedObservers(ctx); err != nil {
+		return err
+	}
+
 	m.logger.Info("started module")
 	m.logger.Info(" -> config", "channels", m.Config())
 	m.registerHandlers(discord)
-	m.startCrons(discord)
+	m.scheduleJobs(ctx, discord)
 	return nil
 }
 
-func (m *Module) startCrons(discord *discordgo.Session) {
-	m.cronsLock.Lock()
-	defer m.cronsLock.Unlock()
-	for _, c := range m.crons {
-		c.Stop()
+func (m *Module) scheduleJobs(ctx context.Context, discord *discordgo.Session) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		catchUp, err := mod.ParseCatchUpPolicy(o.CatchUp)
+
+		if err != nil {
+			m.logger.Error("invalid feed configuration", "channel", o.ID, "feed", o.FeedName(), "err", err)
+			continue
+		}
+
+		job := mod.Job{
+			Name:     mod.JobName(moduleName, "post", configKey(o.ID, o.FeedName())),
+			Module:   moduleName,
+			Schedule: o.CronPattern,
+			Timezone: o.Timezone,
+			CatchUp:  catchUp,
+			Run: func(ctx context.Context) error {
+				return m.Post(discord, o.ID, o.FeedName(), PostRequest{})
+			},
+		}
+
+		if o.mode() == modeAnnounce {
+			// every run announces everything since the last one, so there is
+			// never more than one run to catch up on
+			job.Name = mod.JobName(moduleName, "announce", configKey(o.ID, o.FeedName()))
+
+			if catchUp == mod.CatchUpAll {
+				job.CatchUp = mod.CatchUpOnce
+			}
+
+			job.Run = func(ctx context.Context) error {
+				return m.Announce(ctx, o.ID, o.FeedName())
+			}
+		}
+
+		err = m.scheduler.Register(ctx, job)
+
+		if err != nil {
+			m.logger.Error("error scheduling feed", "channel", o.ID, "feed", o.FeedName(), "err", err)
+		}
+	}
+
+	err := m.scheduler.Register(ctx, mod.Job{
+		Name:     mod.JobName(moduleName, "sync"),
+		Module:   moduleName,
+		Schedule: syncSchedule,
+		CatchUp:  mod.CatchUpOnce,
+		Run:      m.syncProjects,
+	})
+
+	if err != nil {
+		m.logger.Error("error scheduling observation sync", "err", err)
+	}
+
+	err = m.scheduler.Register(ctx, mod.Job{
+		Name:     mod.JobName(moduleName, "prune"),
+		Module:   moduleName,
+		Schedule: "@daily",
+		CatchUp:  mod.CatchUpOnce,
+		Run: func(ctx context.Context) error {
+			return mod.PruneComponentStates(ctx, m.db, moduleName, componentStateRetention)
+		},
+	})
+
+	if err != nil {
+		m.logger.Error("error scheduling component state pruning", "err", err)
 	}
-	m.crons = make([]*cron.Cron, 0, len(m.Config()))
+}
+
+func (m *Module) syncProjects(ctx context.Context) error {
+	var (
+		projects []int64
+		errs     []error
+	)
+
 	for _, o := range m.Config() {
-		pattern := o.CronPattern
-		c := cron.New()
-		c.AddFunc(pattern, func() { m.Post(discord, o.ID) })
-		c.Start()
-		m.crons = append(m.crons, c)
+		// announcements come straight from the api, only showcase feeds pick
+		// from the mirror
+		if o.mode() == modeShowcase && !slices.Contains(projects, o.ProjectID) {
+			projects = append(projects, o.ProjectID)
+		}
+	}
+
+	for _, projectID := range projects {
+		err := m.syncProject(ctx, projectID)
+
+		if err != nil {
+			m.logger.Error("error syncing project observations", "project", projectID, "err", err)
+			errs = append(errs, fmt.Errorf("project %d: %w", projectID, err))
+		}
 	}
+
+	return errors.Join(errs...)
 }
 
 func (m *Module) Name() string {
@@ -112,20 +228,51 @@ func (m *Module) ReloadConfig(
 	}
 
 	m.SetConfig(config)
-	m.startCrons(discord)
+	m.scheduleJobs(ctx, discord)
 	m.logger.Info(" -> config", "channels", m.Config())
 
 	return nil
 }
 
-func (m *Module) channelOptions(channelID string) (ChannelConfig, error) {
+func (m *Module) channelFeeds(channelID string) []ChannelConfig {
+	var feeds []ChannelConfig
+
 	for _, o := range m.Config() {
 		if o.ID == channelID {
+			feeds = append(feeds, o)
+		}
+	}
+
+	return feeds
+}
+
+// feedOptions returns the configuration for a feed in a channel. If no feed
+// name is given, the channel's default feed is used, or its first feed if it
+// doesn't have a default one.
+func (m *Module) feedOptions(channelID, feed string) (ChannelConfig, error) {
+	feeds := m.channelFeeds(channelID)
+
+	if len(feeds) <= 0 {
+		return ChannelConfig{}, errors.New("channel config not found")
+	}
+
+	if feed == "" {
+		for _, o := range feeds {
+			if o.FeedName() == defaultFeed {
+				return o, nil
+			}
+		}
+
+		return feeds[0], nil
+	}
+
+	for _, o := range feeds {
+		if o.FeedName() == feed {
 			return o, nil
 		}
 	}
 
-	return ChannelConfig{}, errors.New("channel config not found")
+	return ChannelConfig{}, fmt.Errorf("feed '%s' not found", feed)
 }
 
 func (m *Module) registerHandlers(discord *discordgo.Session) {
@@ -139,27 +286,17 @@ func (m *Module) registerHandlers(discord *discordgo.Session) {
 	}
 
 	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
-		_, err := m.channelOptions(i.ChannelID)
-
-		if err != nil {
-			d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Wrong channel, bub.",
-				},
-			})
-		}
-
-		if i.ApplicationCommandData().Name == "loadinat" {
-			m.logger.Info("/loadinat called, loading observation to display")
-			go m.Post(discord, i.ChannelID)
-
-			d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Done, observation is loading and will be posted soon!",
-				},
-			})
+		switch i.Type {
+		case discordgo.InteractionMessageComponent:
+			m.handleComponent(d, i)
+		case discordgo.InteractionApplicationCommand:
+			if i.ApplicationCommandData().Name == "loadinat" {
+				m.handleLoadInat(d, i)
+			}
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			if i.ApplicationCommandData().Name == "loadinat" {
+				m.handleLoadInatAutocomplete(d, i)
+			}
 		}
 	})
 }
@@ -180,6 +317,34 @@ func (m *Module) registerSlashCommands(discord *discordgo.Session) {
 		Name:                     "loadinat",
 		Description:              "Load and display a random observation",
 		DefaultMemberPermissions: &adminPermissions,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "feed",
+				Description: "Feed to load the observation for",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "observation",
+				Description: "Post this observation ID",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "user",
+				Description: "Only pick from this iNaturalist user's observations",
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "taxon",
+				Description:  "Only pick observations of this taxon",
+				Autocomplete: true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "preview",
+				Description: "Only show the observation to you, without marking it as seen",
+			},
+		},
 	}
 
 	_, err := discord.ApplicationCommandCreate(discord.State.Application.ID, "", &command)
@@ -192,30 +357,25 @@ func (m *Module) registerSlashCommands(discord *discordgo.Session) {
 }
 
 func (m *Module) findUnseenObservation(
-	channelID string,
-	projectID int64,
+	options ChannelConfig,
+	request PostRequest,
 ) (inat.Observation, error) {
-	config, err := m.channelOptions(channelID)
+	ctx := context.Background()
+	projectID := options.ProjectID
 
-	if err != nil {
-		return inat.Observation{}, err
-	}
-
-	observations, err := m.api.FetchRecentProjectObservations(
-		config.ProjectID,
-		config.PageSize,
-		200,
-	)
+	_, err := m.db.FindObservationSync(ctx, projectID)
 
-	if len(observations) <= 0 {
-		if err != nil {
-			return inat.Observation{}, fmt.Errorf("error fetching observations: %w", err)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the project has never been synced, this is probably the first post
+		// since it was configured
+		if err := m.syncProject(ctx, projectID); err != nil {
+			return inat.Observation{}, fmt.Errorf("error syncing observations: %w", err)
 		}
-
-		return inat.Observation{}, errors.New("no unseen observations found")
+	} else if err != nil {
+		return inat.Observation{}, fmt.Errorf("error fetching sync state: %w", err)
 	}
 
-	o, err := m.selectUnseenObservation(channelID, projectID, observations)
+	o, err := m.selectUnseenObservation(options, request)
 
 	if err != nil {
 		return inat.Observation{}, fmt.Errorf("error fetching unseen observation: %w", err)
@@ -224,220 +384,245 @@ func (m *Module) findUnseenObservation(
 	return o, nil
 }
 
-func (m *Module) Post(discord *discordgo.Session, channelID string) {
-	options, err := m.channelOptions(channelID)
+// Post queues an observation to be posted to a channel's feed. Unless the
+// request asks for a specific observation, an unseen one is picked with the
+// feed's selection strategy.
+func (m *Module) Post(
+	discord *discordgo.Session,
+	channelID string,
+	feed string,
+	request PostRequest,
+) error {
+	options, err := m.feedOptions(channelID, feed)
 
 	if err != nil {
-		return
+		return err
 	}
 
-	m.logger.Info("Attempting to fetch an unseen observation to display")
-	o, err := m.findUnseenObservation(channelID, options.ProjectID)
+	m.logger.Info("Attempting to fetch an observation to display", "feed", options.FeedName())
+	o, err := m.pickObservation(options, request)
 
 	if err != nil {
-		m.logger.Error("error fetching unseen observation", "err", err)
-		return
+		return err
 	}
 
-	taxonName, commonName := o.TaxonNames()
+	dedupeKey := postDedupeKey(moduleName, options, o)
+
+	// a requested observation may already have been posted, it gets its own
+	// key so it's only deduplicated against the same request
+	if request.ObservationID != 0 {
+		dedupeKey = fmt.Sprintf(
+			"%s:load:%s:%d:%s",
+			moduleName,
+			configKey(options.ID, options.FeedName()),
+			o.ID,
+			request.RequestID,
+		)
+	}
 
-	fields := make([]*discordgo.MessageEmbedField, 0)
-	fields = append(fields, &discordgo.MessageEmbedField{
-		Name:  "Taxon",
-		Value: fmt.Sprintf("%s (%s)", taxonName, commonName),
-	})
-	fields = append(fields, &discordgo.MessageEmbedField{
-		Name:  "Our community iNaturalist Project",
-		Value: fmt.Sprintf("https://inaturalist.org/projects/%d", options.ProjectID),
-	})
+	queued, err := m.enqueueObservation(context.Background(), options, o, moduleName, dedupeKey)
 
-	photos := o.Photos
+	if err != nil {
+		return err
+	}
 
-	if len(photos) > 5 {
-		photos = photos[:5]
+	if !queued {
+		// the observation won't be marked as seen until the queued post is
+		// delivered, so it can be picked again while discord is unavailable
+		return fmt.Errorf("error posting observation %d: %w", o.ID, errAlreadyQueued)
 	}
 
-	files := make([]*discordgo.File, 0, len(photos))
+	m.logger.Info("Displaying observation id", "id", o.ID, "user", o.User.Username)
+	return nil
+}
+
+// enqueueObservation queues the post for an observation. `kind` decides what
+// happens once it has been delivered, see `markObservationAsSeen`.
+func (m *Module) enqueueObservation(
+	ctx context.Context,
+	options ChannelConfig,
+	o inat.Observation,
+	kind string,
+	dedupeKey string,
+) (bool, error) {
+	stateID, err := mod.SaveComponentState(ctx, m.db, moduleName, postState{
+		ChannelID:     options.ID,
+		Feed:          options.FeedName(),
+		ProjectID:     options.ProjectID,
+		ObservationID: o.ID,
+	})
 
-	for _, photo := range photos {
-		res, err := http.Get(photo.MediumURL)
+	if err != nil {
+		return false, err
+	}
 
-		if err != nil {
-			m.logger.Error(
-				"unable to retrieve data for photo",
-				"photo",
-				photo.MediumURL,
-				"err",
-				err,
-			)
-			continue
-		}
+	message := observationMessage(o, options)
+	message.Components = postComponents(stateID, o)
 
-		defer res.Body.Close()
-		files = append(files, &discordgo.File{
-			Name:        photo.MediumURL,
-			ContentType: "image/jpeg",
-			Reader:      res.Body,
-		})
+	// observers who have linked their discord account get a mention
+	if account, err := m.db.FindInatAccountByInatUser(ctx, o.User.ID); err == nil {
+		message.Content = fmt.Sprintf("Nice find, <@%s>!", account.DiscordUserID)
 	}
 
-	discord.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
-		Files: files,
-		Embed: &discordgo.MessageEmbed{
-			URL:   fmt.Sprintf("https://inaturalist.org/observations/%d", o.ID),
-			Title: fmt.Sprintf("%s has spotted something new!", o.Username),
-			Author: &discordgo.MessageEmbedAuthor{
-				Name:    o.User.Username,
-				URL:     fmt.Sprintf("https://inaturalist.org/people/%d", o.UserID),
-				IconURL: o.User.UserIconURL,
-			},
-			Color:  2123412,
-			Fields: fields,
+	queued, err := m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      kind,
+		ChannelID: options.ID,
+		DedupeKey: dedupeKey,
+		Meta: postMeta{
+			ChannelID:     options.ID,
+			Feed:          options.FeedName(),
+			ProjectID:     options.ProjectID,
+			ObservationID: o.ID,
+			UserID:        o.User.ID,
 		},
+		Message: message,
 	})
 
-	m.logger.Info("Displaying observation id", "id", o.ID, "user", o.Username)
-
-	m.markObservationAsSeen(context.Background(), channelID, o)
-}
+	if err != nil {
+		return false, fmt.Errorf("error queueing observation post: %w", err)
+	}
 
-func (m *Module) DisplayedObservers(channelID string) ([]int64, bool) {
-	m.displayedObserversLock.RLock()
-	defer m.displayedObserversLock.RUnlock()
-	items, ok := m.displayedObservers[channelID]
-	return items, ok
+	return queued, nil
 }
 
-func (m *Module) SetDisplayedObservers(channelID string, do []int64) {
-	m.displayedObserversLock.Lock()
-	defer m.displayedObserversLock.Unlock()
-	m.displayedObservers[channelID] = do
+// postDedupeKey is the dedupe key for a feed's post of an observation
+func postDedupeKey(kind string, options ChannelConfig, o inat.Observation) string {
+	return fmt.Sprintf("%s:%s:%d", kind, configKey(options.ID, options.FeedName()), o.ID)
 }
 
+// markObservationAsSeen records a posted observation once discord has
+// confirmed the post was delivered
 func (m *Module) markObservationAsSeen(
 	ctx context.Context,
-	channelID string,
-	o inat.Observation,
-) (store.SeenObservation, error) {
-	options, err := m.channelOptions(channelID)
+	data []byte,
+	_ *discordgo.Message,
+) error {
+	var meta postMeta
 
-	if err != nil {
-		return store.SeenObservation{}, err
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("could not parse observation post meta: %w", err)
 	}
 
-	displayed, ok := m.DisplayedObservers(channelID)
-
-	if !ok {
-		displayed = make([]int64, 0)
+	// digests aren't for a single observation
+	if meta.ObservationID == 0 {
+		return nil
 	}
 
-	if !slices.Contains(displayed, o.UserID) {
-		displayed = append(displayed, o.UserID)
+	options := ChannelConfig{ID: meta.ChannelID, Feed: meta.Feed, ProjectID: meta.ProjectID}
+
+	if err := m.recordDisplayedObserver(ctx, options, meta.UserID); err != nil {
+		return err
 	}
 
-	m.SetDisplayedObservers(channelID, displayed)
-	seen, err := m.db.CreateSeenObservation(
+	_, err := m.db.CreateSeenObservation(
 		ctx,
 		store.CreateSeenObservationParams{
-			ID:        o.ID,
-			ProjectID: options.ProjectID,
-			ChannelID: channelID,
+			ID:        meta.ObservationID,
+			ProjectID: meta.ProjectID,
+			ChannelID: meta.ChannelID,
+			Feed:      meta.Feed,
 		},
 	)
 
 	if err != nil {
-		return store.SeenObservation{}, fmt.Errorf("error saving seen observation: %w", err)
+		return fmt.Errorf("error saving seen observation: %w", err)
 	}
 
-	return seen, nil
+	return nil
 }
 
 func (m *Module) selectUnseenObservation(
-	channelID string,
-	projectID int64,
-	observations []inat.Observation,
+	options ChannelConfig,
+	request PostRequest,
 ) (inat.Observation, error) {
-	var (
-		observationIds     []int64
-		unseen             []inat.Observation
-		seenIds            []int64
-		observerMap        = make(map[int64][]inat.Observation)
-		potentialObservers []int64
+	ctx := context.Background()
+	projectID := options.ProjectID
+	strategy, err := NewStrategy(
+		options.Strategy,
+		rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
 	)
 
-	displayed, ok := m.DisplayedObservers(channelID)
-
-	if !ok {
-		displayed = make([]int64, 0)
+	if err != nil {
+		return inat.Observation{}, err
 	}
 
-	for _, o := range observations {
-		observationIds = append(observationIds, o.ID)
-	}
-
-	seen, err := m.db.FindObservations(context.Background(), store.FindObservationsParams{
-		ID:        observationIds,
-		ProjectID: projectID,
-		ChannelID: channelID,
-	})
+	displayed, _ := m.DisplayedObservers(options.ID, projectID, options.FeedName())
+	params := options.Filters.candidateParams()
+	params.ChannelID = options.ID
+	params.Feed = options.FeedName()
+	params.ProjectID = projectID
+	params.UserLogin = request.UserLogin
+	params.TaxonID = request.TaxonID
+	candidates, err := m.db.FindUnseenObservationCandidates(ctx, params)
 
 	if err != nil {
-		return inat.Observation{}, fmt.Errorf("error selecting seen observations: %w", err)
+		return inat.Observation{}, fmt.Errorf("error selecting unseen observations: %w", err)
 	}
 
-	for _, o := range seen {
-		seenIds = append(seenIds, o.ID)
-	}
-
-	for _, o := range observations {
-		if !slices.Contains(seenIds, o.ID) {
-			unseen = append(unseen, o)
-
-			items, ok := observerMap[o.UserID]
+	for range maxSelectAttempts {
+		c, err := strategy.Select(candidates, displayed)
 
-			if !ok {
-				items = make([]inat.Observation, 0)
-			}
+		if err != nil {
+			return inat.Observation{}, err
+		}
 
-			items = append(items, o)
-			observerMap[o.UserID] = items
+		o, err := m.refreshObservation(ctx, projectID, c.ID)
 
-			if !slices.Contains(displayed, o.UserID) {
-				potentialObservers = append(potentialObservers, o.UserID)
-			}
+		if !errors.Is(err, errObservationNotFound) {
+			return o, err
 		}
-	}
 
-	if len(unseen) <= 0 {
-		return inat.Observation{}, errors.New("no unseen observations found")
+		candidates = slices.DeleteFunc(candidates, func(other candidate) bool {
+			return other.ID == c.ID
+		})
 	}
 
-	if len(potentialObservers) <= 0 {
-		potentialObservers = slices.Collect(maps.Keys(observerMap))
-		displayed = displayed[:0]
-		m.SetDisplayedObservers(channelID, displayed)
-	}
+	return inat.Observation{}, errNoCandidates
+}
 
-	rand.Shuffle(len(potentialObservers), func(i, j int) {
-		potentialObservers[i], potentialObservers[j] = potentialObservers[j], potentialObservers[i]
-	})
+// refreshObservation re-fetches a mirrored observation before it's posted.
+// Observations that were deleted, or removed from the project, since they
+// were synced are dropped from the mirror. If iNaturalist can't be reached,
+// the mirrored copy is used.
+func (m *Module) refreshObservation(
+	ctx context.Context,
+	projectID int64,
+	id int64,
+) (inat.Observation, error) {
+	o, err := m.fetchProjectObservation(projectID, id)
 
-	observerId := potentialObservers[0]
-	items, ok := observerMap[observerId]
+	if errors.Is(err, errObservationNotFound) {
+		m.logger.Info("observation is gone, removing it", "project", projectID, "id", id)
+		deleteErr := m.db.DeleteProjectObservation(ctx, store.DeleteProjectObservationParams{
+			ProjectID: projectID,
+			ID:        id,
+		})
 
-	if !ok || len(items) <= 0 {
-		return inat.Observation{}, fmt.Errorf(
-			"could not find unseen observations for observer %d",
-			observerId,
-		)
+		if deleteErr != nil {
+			return inat.Observation{}, fmt.Errorf("error removing observation %d: %w", id, deleteErr)
+		}
+
+		return inat.Observation{}, err
 	}
 
-	rand.Shuffle(len(items), func(i, j int) {
-		items[i], items[j] = items[j], items[i]
-	})
+	if err == nil {
+		if err := m.saveObservation(ctx, projectID, o); err != nil {
+			return inat.Observation{}, err
+		}
+
+		return o, nil
+	}
 
-	observation := items[0]
+	m.logger.Warn("error refreshing observation, using the mirrored copy", "id", id, "err", err)
+	row, err := m.db.FindProjectObservation(ctx, store.FindProjectObservationParams{
+		ProjectID: projectID,
+		ID:        id,
+	})
 
-	return observation, nil
+	if err != nil {
+		return inat.Observation{}, fmt.Errorf("error fetching observation %d: %w", id, err)
+	}
 
+	return observationFromRow(row)
 }
@@ -0,0 +1,109 @@
+package inatobs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+// rotationKey identifies the observer rotation of a project in a channel's
+// feed
+type rotationKey struct {
+	channelID string
+	projectID int64
+	feed      string
+}
+
+func (m *Module) DisplayedObservers(channelID string, projectID int64, feed string) ([]int64, bool) {
+	m.displayedObserversLock.RLock()
+	defer m.displayedObserversLock.RUnlock()
+	items, ok := m.displayedObservers[rotationKey{channelID, projectID, feed}]
+	return items, ok
+}
+
+func (m *Module) SetDisplayedObservers(
+	channelID string,
+	projectID int64,
+	feed string,
+	do []int64,
+) {
+	m.displayedObserversLock.Lock()
+	defer m.displayedObserversLock.Unlock()
+	m.displayedObservers[rotationKey{channelID, projectID, feed}] = do
+}
+
+func (m *Module) loadDisplayedObservers(ctx context.Context) error {
+	rows, err := m.db.FindDisplayedObservers(ctx, store.FindDisplayedObserversParams{})
+
+	if err != nil {
+		return fmt.Errorf("error loading displayed observers: %w", err)
+	}
+
+	displayed := make(map[rotationKey][]int64)
+
+	for _, row := range rows {
+		key := rotationKey{row.ChannelID, row.ProjectID, row.Feed}
+		displayed[key] = append(displayed[key], row.UserID)
+	}
+
+	m.displayedObserversLock.Lock()
+	defer m.displayedObserversLock.Unlock()
+	m.displayedObservers = displayed
+	return nil
+}
+
+func (m *Module) recordDisplayedObserver(
+	ctx context.Context,
+	options ChannelConfig,
+	userID int64,
+) error {
+	channelID, projectID, feed := options.ID, options.ProjectID, options.FeedName()
+	displayed, _ := m.DisplayedObservers(channelID, projectID, feed)
+
+	if slices.Contains(displayed, userID) {
+		// every observer has had a turn, start the rotation over
+		if err := m.ResetDisplayedObservers(ctx, channelID, projectID, feed); err != nil {
+			return err
+		}
+
+		displayed = nil
+	}
+
+	err := m.db.CreateDisplayedObserver(ctx, store.CreateDisplayedObserverParams{
+		ChannelID: channelID,
+		ProjectID: projectID,
+		Feed:      feed,
+		UserID:    userID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving displayed observer: %w", err)
+	}
+
+	m.SetDisplayedObservers(channelID, projectID, feed, append(slices.Clone(displayed), userID))
+	return nil
+}
+
+// ResetDisplayedObservers clears the observer rotation for a project in a
+// channel's feed, so every observer is eligible to be shown again
+func (m *Module) ResetDisplayedObservers(
+	ctx context.Context,
+	channelID string,
+	projectID int64,
+	feed string,
+) error {
+	err := m.db.DeleteDisplayedObservers(ctx, store.DeleteDisplayedObserversParams{
+		ChannelID: channelID,
+		ProjectID: projectID,
+		Feed:      feed,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error resetting displayed observers: %w", err)
+	}
+
+	m.SetDisplayedObservers(channelID, projectID, feed, nil)
+	return nil
+}
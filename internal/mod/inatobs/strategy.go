@@ -0,0 +1,194 @@
+package inatobs
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	defaultStrategy = "observer"
+	strategies      = map[string]func(*rand.Rand) Strategy{
+		"random":   func(rng *rand.Rand) Strategy { return randomStrategy{rng: rng} },
+		"newest":   func(rng *rand.Rand) Strategy { return newestStrategy{} },
+		"faves":    func(rng *rand.Rand) Strategy { return favesStrategy{rng: rng} },
+		"research": func(rng *rand.Rand) Strategy { return researchStrategy{rng: rng} },
+		"rarest":   func(rng *rand.Rand) Strategy { return rarestStrategy{rng: rng} },
+		"observer": func(rng *rand.Rand) Strategy { return observerStrategy{rng: rng} },
+	}
+
+	errNoCandidates = errors.New("no unseen observations found")
+)
+
+type candidate = store.FindUnseenObservationCandidatesRow
+
+// Strategy picks which of the unseen observations in a project is posted
+// next. `displayed` holds the observers that have been shown recently in the
+// channel, most recent last.
+type Strategy interface {
+	Select(candidates []candidate, displayed []int64) (candidate, error)
+}
+
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+
+	for name := range strategies {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+	return names
+}
+
+func NewStrategy(name string, rng *rand.Rand) (Strategy, error) {
+	if name == "" {
+		name = defaultStrategy
+	}
+
+	f, ok := strategies[name]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown selection strategy '%s'", name)
+	}
+
+	return f(rng), nil
+}
+
+func pick(rng *rand.Rand, candidates []candidate) (candidate, error) {
+	if len(candidates) <= 0 {
+		return candidate{}, errNoCandidates
+	}
+
+	return candidates[rng.IntN(len(candidates))], nil
+}
+
+// pickBest returns a random candidate among those that tie for the highest
+// score
+func pickBest(
+	rng *rand.Rand,
+	candidates []candidate,
+	score func(candidate) int64,
+) (candidate, error) {
+	var best []candidate
+
+	for _, c := range candidates {
+		if len(best) <= 0 || score(c) > score(best[0]) {
+			best = []candidate{c}
+		} else if score(c) == score(best[0]) {
+			best = append(best, c)
+		}
+	}
+
+	return pick(rng, best)
+}
+
+// randomStrategy picks any unseen observation
+type randomStrategy struct {
+	rng *rand.Rand
+}
+
+func (s randomStrategy) Select(candidates []candidate, _ []int64) (candidate, error) {
+	return pick(s.rng, candidates)
+}
+
+// newestStrategy picks the most recently uploaded unseen observation
+type newestStrategy struct{}
+
+func (s newestStrategy) Select(candidates []candidate, _ []int64) (candidate, error) {
+	if len(candidates) <= 0 {
+		return candidate{}, errNoCandidates
+	}
+
+	return slices.MaxFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.ID, b.ID)
+	}), nil
+}
+
+// favesStrategy picks the unseen observation with the most faves
+type favesStrategy struct {
+	rng *rand.Rand
+}
+
+func (s favesStrategy) Select(candidates []candidate, _ []int64) (candidate, error) {
+	return pickBest(s.rng, candidates, func(c candidate) int64 { return c.FavesCount })
+}
+
+// researchStrategy picks any unseen research grade observation
+type researchStrategy struct {
+	rng *rand.Rand
+}
+
+func (s researchStrategy) Select(candidates []candidate, _ []int64) (candidate, error) {
+	var research []candidate
+
+	for _, c := range candidates {
+		if c.QualityGrade == "research" {
+			research = append(research, c)
+		}
+	}
+
+	return pick(s.rng, research)
+}
+
+// rarestStrategy picks an unseen observation of the taxon that has been
+// observed the fewest times in the project
+type rarestStrategy struct {
+	rng *rand.Rand
+}
+
+func (s rarestStrategy) Select(candidates []candidate, _ []int64) (candidate, error) {
+	var identified []candidate
+
+	for _, c := range candidates {
+		if c.TaxonID != 0 {
+			identified = append(identified, c)
+		}
+	}
+
+	return pickBest(s.rng, identified, func(c candidate) int64 { return -c.TaxonCount })
+}
+
+// observerStrategy picks a random observer that hasn't been shown recently,
+// and then a random unseen observation from them. Once every observer has
+// been shown, any observer is fair game again.
+type observerStrategy struct {
+	rng *rand.Rand
+}
+
+func (s observerStrategy) Select(candidates []candidate, displayed []int64) (candidate, error) {
+	var (
+		observers          []int64
+		potentialObservers []int64
+		found              = make(map[int64]bool)
+	)
+
+	for _, c := range candidates {
+		if !found[c.UserID] {
+			found[c.UserID] = true
+			observers = append(observers, c.UserID)
+
+			if !slices.Contains(displayed, c.UserID) {
+				potentialObservers = append(potentialObservers, c.UserID)
+			}
+		}
+	}
+
+	if len(observers) <= 0 {
+		return candidate{}, errNoCandidates
+	}
+
+	if len(potentialObservers) <= 0 {
+		potentialObservers = observers
+	}
+
+	observerID := potentialObservers[s.rng.IntN(len(potentialObservers))]
+	items := slices.DeleteFunc(slices.Clone(candidates), func(c candidate) bool {
+		return c.UserID != observerID
+	})
+
+	return pick(s.rng, items)
+}
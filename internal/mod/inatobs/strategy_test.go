@@ -0,0 +1,106 @@
+package inatobs
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+var testCandidates = []candidate{
+	{ID: 1, UserID: 10, TaxonID: 100, QualityGrade: "needs_id", FavesCount: 2, TaxonCount: 5},
+	{ID: 2, UserID: 10, TaxonID: 101, QualityGrade: "research", FavesCount: 5, TaxonCount: 1},
+	{ID: 3, UserID: 20, TaxonID: 102, QualityGrade: "research", FavesCount: 5, TaxonCount: 1},
+	{ID: 4, UserID: 30, TaxonID: 0, QualityGrade: "casual", FavesCount: 0, TaxonCount: 0},
+	{ID: 5, UserID: 20, TaxonID: 103, QualityGrade: "needs_id", FavesCount: 1, TaxonCount: 3},
+	{ID: 6, UserID: 30, TaxonID: 104, QualityGrade: "research", FavesCount: 3, TaxonCount: 2},
+}
+
+func TestStrategySelect(t *testing.T) {
+	tests := []struct {
+		name       string
+		strategy   string
+		candidates []candidate
+		displayed  []int64
+		want       int64
+	}{
+		{name: "random", strategy: "random", candidates: testCandidates, want: 5},
+		{name: "newest", strategy: "newest", candidates: testCandidates, want: 6},
+		{name: "faves picks among the most faved", strategy: "faves", candidates: testCandidates, want: 2},
+		{name: "research", strategy: "research", candidates: testCandidates, want: 6},
+		{name: "rarest skips unidentified", strategy: "rarest", candidates: testCandidates, want: 2},
+		{
+			name:       "observer skips displayed observers",
+			strategy:   "observer",
+			candidates: testCandidates,
+			displayed:  []int64{10, 20},
+			want:       4,
+		},
+		{
+			name:       "observer falls back once every observer was displayed",
+			strategy:   "observer",
+			candidates: testCandidates,
+			displayed:  []int64{10, 20, 30},
+			want:       4,
+		},
+		{name: "default is observer", strategy: "", candidates: testCandidates, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewStrategy(tt.strategy, rand.New(rand.NewPCG(1, 2)))
+
+			if err != nil {
+				t.Fatalf("NewStrategy(%q) error: %v", tt.strategy, err)
+			}
+
+			got, err := strategy.Select(tt.candidates, tt.displayed)
+
+			if err != nil {
+				t.Fatalf("Select() error: %v", err)
+			}
+
+			if got.ID != tt.want {
+				t.Errorf("Select() = %d, want %d", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestStrategyNoCandidates(t *testing.T) {
+	unidentified := []candidate{{ID: 1, UserID: 10, QualityGrade: "casual"}}
+
+	tests := []struct {
+		name       string
+		strategy   string
+		candidates []candidate
+	}{
+		{name: "random", strategy: "random"},
+		{name: "newest", strategy: "newest"},
+		{name: "faves", strategy: "faves"},
+		{name: "research", strategy: "research"},
+		{name: "rarest", strategy: "rarest"},
+		{name: "observer", strategy: "observer"},
+		{name: "research without research grade", strategy: "research", candidates: unidentified},
+		{name: "rarest without identified", strategy: "rarest", candidates: unidentified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewStrategy(tt.strategy, rand.New(rand.NewPCG(1, 2)))
+
+			if err != nil {
+				t.Fatalf("NewStrategy(%q) error: %v", tt.strategy, err)
+			}
+
+			if _, err := strategy.Select(tt.candidates, nil); !errors.Is(err, errNoCandidates) {
+				t.Errorf("Select() error = %v, want %v", err, errNoCandidates)
+			}
+		})
+	}
+}
+
+func TestNewStrategyUnknown(t *testing.T) {
+	if _, err := NewStrategy("nope", rand.New(rand.NewPCG(1, 2))); err == nil {
+		t.Error("NewStrategy(\"nope\") returned no error")
+	}
+}
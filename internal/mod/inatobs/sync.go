@@ -0,0 +1,246 @@
+package inatobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	syncSchedule     = "@every 15m"
+	syncPageSize     = 200
+	syncMaxPages     = 25
+	syncBackfillRate = 10
+	syncRequestDelay = time.Second
+)
+
+// syncProject brings the local mirror of a project's observations up to
+// date. Each run pulls in anything newer than what has been seen so far,
+// refreshes observations that have changed since the last run, and walks a
+// few more pages back into the project's history until the backfill is
+// complete. Observations that are deleted or leave the project are only
+// noticed when they're picked, see `refreshObservation`.
+func (m *Module) syncProject(ctx context.Context, projectID int64) error {
+	m.syncLock.Lock()
+	defer m.syncLock.Unlock()
+
+	state, err := m.db.FindObservationSync(ctx, projectID)
+
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("error fetching sync state: %w", err)
+		}
+
+		state = store.ObservationSync{ProjectID: projectID}
+	}
+
+	startedAt := time.Now().UTC()
+	logger := m.logger.With("project", projectID)
+	logger.Info("syncing project observations", "newest", state.NewestID, "oldest", state.OldestID)
+
+	if state.NewestID == 0 {
+		// nothing has been synced yet, start with the newest page so there
+		// is something to post while the history is backfilled
+		items, err := m.syncPage(ctx, projectID, url.Values{"order": {"desc"}})
+
+		if err != nil {
+			return err
+		}
+
+		if len(items) <= 0 {
+			logger.Info("project has no observations")
+			state.BackfillComplete = true
+			return m.saveSyncState(ctx, state, startedAt)
+		}
+
+		state.NewestID = items[0].ID
+		state.OldestID = items[len(items)-1].ID
+		state.BackfillComplete = len(items) < syncPageSize
+	} else {
+		if err := m.syncRefresh(ctx, projectID, state.RefreshedAt); err != nil {
+			return err
+		}
+	}
+
+	for range syncMaxPages {
+		items, err := m.syncPage(ctx, projectID, url.Values{
+			"order":    {"asc"},
+			"id_above": {strconv.FormatInt(state.NewestID, 10)},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(items) > 0 {
+			state.NewestID = items[len(items)-1].ID
+		}
+
+		if len(items) < syncPageSize {
+			break
+		}
+	}
+
+	for range syncBackfillRate {
+		if state.BackfillComplete {
+			break
+		}
+
+		items, err := m.syncPage(ctx, projectID, url.Values{
+			"order":    {"desc"},
+			"id_below": {strconv.FormatInt(state.OldestID, 10)},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(items) > 0 {
+			state.OldestID = items[len(items)-1].ID
+		}
+
+		state.BackfillComplete = len(items) < syncPageSize
+	}
+
+	if err := m.saveSyncState(ctx, state, startedAt); err != nil {
+		return err
+	}
+
+	logger.Info(
+		"project observations synced",
+		"newest",
+		state.NewestID,
+		"oldest",
+		state.OldestID,
+		"backfill_complete",
+		state.BackfillComplete,
+	)
+
+	return nil
+}
+
+// saveSyncState records how far a project has been synced, `refreshedAt` is
+// when the sync started
+func (m *Module) saveSyncState(
+	ctx context.Context,
+	state store.ObservationSync,
+	refreshedAt time.Time,
+) error {
+	_, err := m.db.SaveObservationSync(ctx, store.SaveObservationSyncParams{
+		ProjectID:        state.ProjectID,
+		NewestID:         state.NewestID,
+		OldestID:         state.OldestID,
+		BackfillComplete: state.BackfillComplete,
+		RefreshedAt:      refreshedAt,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving sync state: %w", err)
+	}
+
+	return nil
+}
+
+// syncRefresh re-fetches observations that were updated since the last sync,
+// so identifications, faves and photo changes make it into the mirror.
+func (m *Module) syncRefresh(ctx context.Context, projectID int64, since time.Time) error {
+	for page := 1; page <= syncMaxPages; page++ {
+		items, err := m.syncPage(ctx, projectID, url.Values{
+			"order":         {"asc"},
+			"updated_since": {since.Format(time.RFC3339)},
+			"page":          {strconv.Itoa(page)},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(items) < syncPageSize {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) syncPage(
+	ctx context.Context,
+	projectID int64,
+	params url.Values,
+) ([]inat.Observation, error) {
+	params.Set("project_id", strconv.FormatInt(projectID, 10))
+	params.Set("order_by", "id")
+	params.Set("per_page", strconv.Itoa(syncPageSize))
+
+	// be kind to the iNaturalist api, they ask for no more than one request
+	// per second
+	time.Sleep(syncRequestDelay)
+
+	r, err := m.api.FetchObservations(params)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching observations: %w", err)
+	}
+
+	for _, o := range r.Results {
+		if err := m.saveObservation(ctx, projectID, o); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.Results, nil
+}
+
+func (m *Module) saveObservation(ctx context.Context, projectID int64, o inat.Observation) error {
+	data, err := json.Marshal(o)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.db.UpsertObservation(ctx, store.UpsertObservationParams{
+		ID:            o.ID,
+		ProjectID:     projectID,
+		UserID:        o.User.ID,
+		UserLogin:     o.User.Username,
+		TaxonID:       o.Taxon.ID,
+		IconicTaxon:   o.Taxon.IconicTaxonName,
+		TaxonAncestry: taxonAncestry(o),
+		PlaceIds:      joinIDs(o.PlaceIDs),
+		QualityGrade:  o.QualityGrade,
+		FavesCount:    o.FavesCount,
+		PhotoCount:    int64(len(o.Photos)),
+		ObservedOn:    o.ObservedOn,
+		Data:          data,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving observation %d: %w", o.ID, err)
+	}
+
+	return nil
+}
+
+func observationFromRow(row store.Observation) (inat.Observation, error) {
+	var o inat.Observation
+
+	data, ok := row.Data.([]byte)
+
+	if !ok {
+		return o, fmt.Errorf("unexpected data type for observation %d", row.ID)
+	}
+
+	if err := json.Unmarshal(data, &o); err != nil {
+		return o, fmt.Errorf("could not parse observation %d: %w", row.ID, err)
+	}
+
+	return o, nil
+}
@@ -0,0 +1,24 @@
+package mod
+
+import "github.com/bwmarrin/discordgo"
+
+// InteractionUser returns who triggered an interaction, in a guild or a dm
+func InteractionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+
+	return i.User
+}
+
+// RespondEphemeral answers an interaction with a message only the member who
+// triggered it can see
+func RespondEphemeral(d *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
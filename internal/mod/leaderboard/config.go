@@ -0,0 +1,101 @@
+package leaderboard
+
+import (
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+const (
+	periodWeek  = "week"
+	periodMonth = "month"
+)
+
+var (
+	periods          = []string{periodWeek, periodMonth}
+	defaultSize      = int64(5)
+	defaultSchedules = map[string]string{
+		periodWeek:  "0 9 * * 1",
+		periodMonth: "0 9 1 * *",
+	}
+)
+
+// ChannelConfig configures a leaderboard that is posted to a channel at the
+// end of every week or month
+type ChannelConfig struct {
+	ID          string `json:"id"`
+	ProjectID   int64  `json:"inat_project_id"`
+	Period      string `json:"period"`
+	CronPattern string `json:"cron_pattern"`
+	Timezone    string `json:"timezone"`
+	Size        int64  `json:"size"`
+}
+
+func (c ChannelConfig) period() string {
+	if c.Period == "" {
+		return periodWeek
+	}
+
+	return c.Period
+}
+
+func (c ChannelConfig) schedule() string {
+	if c.CronPattern == "" {
+		return defaultSchedules[c.period()]
+	}
+
+	return c.CronPattern
+}
+
+func (c ChannelConfig) size() int {
+	if c.Size <= 0 {
+		return int(defaultSize)
+	}
+
+	return int(c.Size)
+}
+
+func ConfigCommandOptions() mod.ConfigCommandOptions {
+	args := []*glap.Arg{
+		glap.NewArg("channel-id").Short('c').Required(true).Help("Channel CHANNEL_ID"),
+		glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID"),
+		glap.NewArg("period").
+			Default(periodWeek).
+			PossibleValues(periods...).
+			Help("Post a leaderboard for every PERIOD"),
+		glap.NewArg("schedule-pattern").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("When to post, defaults to the start of every period PATTERN"),
+		glap.NewArg("timezone").
+			Validator(func(v string) error { return mod.ValidateSchedule("@daily", v) }).
+			Help("Timezone periods start in, defaults to UTC TIMEZONE"),
+		glap.NewArg("size").Default("5").Help("Show the top COUNT of each list"),
+	}
+
+	return mod.ConfigCommandOptions{
+		Args:       args,
+		KeyArgs:    []string{"channel-id"},
+		ModuleName: moduleName,
+		GetKey: func(m *glap.Matches) string {
+			v, _ := m.GetString("channel-id")
+			return v
+		},
+		GetData: func(m *glap.Matches) any {
+			channelID, _ := m.GetString("channel-id")
+			projectID, _ := m.GetInt64("project-id")
+			period, _ := m.GetString("period")
+			cronPattern, _ := m.GetString("schedule-pattern")
+			timezone, _ := m.GetString("timezone")
+			size, _ := m.GetInt64("size")
+
+			return ChannelConfig{
+				ID:          channelID,
+				ProjectID:   projectID,
+				Period:      period,
+				CronPattern: cronPattern,
+				Timezone:    timezone,
+				Size:        size,
+			}
+		},
+	}
+}
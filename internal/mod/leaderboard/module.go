@@ -0,0 +1,320 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName      = "leaderboard"
+	embedColor      = 3447003
+	apiRequestDelay = time.Second
+	rankMedals      = []string{":first_place:", ":second_place:", ":third_place:"}
+)
+
+type Module struct {
+	api        inat.Api
+	db         *store.Queries
+	scheduler  *mod.Scheduler
+	outbox     *mod.Outbox
+	logger     *slog.Logger
+	config     []ChannelConfig
+	configLock sync.RWMutex
+}
+
+func New(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (*Module, error) {
+	return &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		outbox:    outbox,
+		logger:    logger,
+	}, nil
+}
+
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, outbox, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Config() []ChannelConfig {
+	m.configLock.RLock()
+	defer m.configLock.RUnlock()
+	return m.config
+}
+
+func (m *Module) SetConfig(config []ChannelConfig) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	m.config = config
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.logger.Info("started module")
+	m.logger.Info(" -> config", "channels", m.Config())
+	m.scheduleJobs(ctx)
+	return nil
+}
+
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.scheduleJobs(ctx)
+	m.logger.Info(" -> config", "channels", m.Config())
+	return nil
+}
+
+func (m *Module) scheduleJobs(ctx context.Context) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		err := m.scheduler.Register(ctx, mod.Job{
+			Name:     mod.JobName(moduleName, "post", o.ID),
+			Module:   moduleName,
+			Schedule: o.schedule(),
+			Timezone: o.Timezone,
+			CatchUp:  mod.CatchUpOnce,
+			Run: func(ctx context.Context) error {
+				return m.Post(ctx, o.ID)
+			},
+		})
+
+		if err != nil {
+			m.logger.Error("error scheduling leaderboard", "channel", o.ID, "err", err)
+		}
+	}
+}
+
+func (m *Module) channelConfig(channelID string) (ChannelConfig, error) {
+	for _, o := range m.Config() {
+		if o.ID == channelID {
+			return o, nil
+		}
+	}
+
+	return ChannelConfig{}, errors.New("channel config not found")
+}
+
+// Post posts the leaderboard for the last complete period, compared to the
+// period before it. Each period is only posted once.
+func (m *Module) Post(ctx context.Context, channelID string) error {
+	options, err := m.channelConfig(channelID)
+
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(options.Timezone)
+
+	if err != nil {
+		return fmt.Errorf("invalid timezone '%s': %w", options.Timezone, err)
+	}
+
+	start, end := periodBounds(options.period(), time.Now().In(loc))
+	prevStart, prevEnd := periodBounds(options.period(), start)
+
+	// the current period is always refreshed, observations and
+	// identifications are still being added after the period is over
+	cur, err := m.loadSnapshot(ctx, options, start, end, true)
+
+	if err != nil {
+		return err
+	}
+
+	time.Sleep(apiRequestDelay)
+	prev, err := m.loadSnapshot(ctx, options, prevStart, prevEnd, false)
+
+	if err != nil {
+		return err
+	}
+
+	embed := m.leaderboardEmbed(options, start, end, cur, prev)
+
+	queued, err := m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      moduleName,
+		ChannelID: options.ID,
+		DedupeKey: fmt.Sprintf(
+			"%s:%s:%d:%s:%s",
+			moduleName,
+			options.ID,
+			options.ProjectID,
+			options.period(),
+			start.Format(time.DateOnly),
+		),
+		Message: mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing leaderboard: %w", err)
+	}
+
+	if queued {
+		m.logger.Info(
+			"posting leaderboard",
+			"channel",
+			options.ID,
+			"period",
+			options.period(),
+			"start",
+			start.Format(time.DateOnly),
+		)
+	}
+
+	return nil
+}
+
+func (m *Module) leaderboardEmbed(
+	options ChannelConfig,
+	start, end time.Time,
+	cur, prev snapshot,
+) *discordgo.MessageEmbed {
+	title := "Our project"
+	embed := &discordgo.MessageEmbed{
+		URL:   fmt.Sprintf("https://inaturalist.org/projects/%d", options.ProjectID),
+		Color: embedColor,
+	}
+
+	if project, err := m.api.FetchProject(options.ProjectID); err != nil {
+		m.logger.Warn("error fetching project", "project", options.ProjectID, "err", err)
+	} else {
+		title = project.Title
+
+		if project.IconURL != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: project.IconURL}
+		}
+	}
+
+	last := end.AddDate(0, 0, -1)
+
+	if options.period() == periodMonth {
+		embed.Title = fmt.Sprintf(":trophy: %s, %s", title, start.Format("January 2006"))
+	} else {
+		embed.Title = fmt.Sprintf(
+			":trophy: %s, week of %s - %s",
+			title,
+			start.Format("Jan 2"),
+			last.Format("Jan 2"),
+		)
+	}
+
+	embed.Description = strings.Join([]string{
+		fmt.Sprintf("**%s** observations%s", mod.FormatCount(cur.Observations), change(cur.Observations, prev.Observations)),
+		fmt.Sprintf("**%s** species%s", mod.FormatCount(cur.Species), change(cur.Species, prev.Species)),
+		fmt.Sprintf("**%s** observers%s", mod.FormatCount(cur.Observers), change(cur.Observers, prev.Observers)),
+	}, "\n")
+
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "Top observers", Value: rankLines(cur.TopObservers, prev.TopObservers)},
+		{Name: "Top identifiers", Value: rankLines(cur.TopIdentifiers, prev.TopIdentifiers)},
+		{Name: "Most observed species", Value: rankLines(cur.TopSpecies, prev.TopSpecies)},
+	}
+
+	embed.Footer = &discordgo.MessageEmbedFooter{
+		Text: fmt.Sprintf("Compared to the previous %s", options.period()),
+	}
+
+	return embed
+}
+
+// change describes how a count changed from the previous period
+func change(cur, prev int64) string {
+	if prev == 0 {
+		return ""
+	}
+
+	percent := (cur - prev) * 100 / prev
+
+	switch {
+	case percent > 0:
+		return fmt.Sprintf(" (▲ %d%%)", percent)
+	case percent < 0:
+		return fmt.Sprintf(" (▼ %d%%)", -percent)
+	default:
+		return ""
+	}
+}
+
+// rankLines lists the entries of a top list, with how far each one moved
+// since the previous period
+func rankLines(cur, prev []entry) string {
+	if len(cur) == 0 {
+		return "Nobody yet!"
+	}
+
+	prevRanks := make(map[int64]int, len(prev))
+
+	for i, e := range prev {
+		prevRanks[e.ID] = i
+	}
+
+	lines := make([]string, 0, len(cur))
+
+	for i, e := range cur {
+		rank := fmt.Sprintf("%d.", i+1)
+
+		if i < len(rankMedals) {
+			rank = rankMedals[i]
+		}
+
+		movement := ""
+
+		if p, ok := prevRanks[e.ID]; !ok {
+			movement = " :new:"
+		} else if p > i {
+			movement = fmt.Sprintf(" ▲%d", p-i)
+		} else if p < i {
+			movement = fmt.Sprintf(" ▼%d", i-p)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s, %s%s", rank, e.Name, mod.FormatCount(e.Count), movement))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,183 @@
+package leaderboard
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+// snapshot is what a project looked like over one period. Snapshots are
+// stored, so the next leaderboard can be compared to this one.
+type snapshot struct {
+	TopObservers   []entry `json:"top_observers"`
+	TopIdentifiers []entry `json:"top_identifiers"`
+	TopSpecies     []entry `json:"top_species"`
+	Observations   int64   `json:"observations"`
+	Species        int64   `json:"species"`
+	Observers      int64   `json:"observers"`
+}
+
+type entry struct {
+	Name  string `json:"name"`
+	ID    int64  `json:"id"`
+	Count int64  `json:"count"`
+}
+
+// periodBounds returns the last complete period before `now`. Weeks start on
+// monday.
+func periodBounds(period string, now time.Time) (time.Time, time.Time) {
+	y, mo, d := now.Date()
+
+	if period == periodMonth {
+		end := time.Date(y, mo, 1, 0, 0, 0, 0, now.Location())
+		return end.AddDate(0, -1, 0), end
+	}
+
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	end := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	return end.AddDate(0, 0, -7), end
+}
+
+// loadSnapshot returns the snapshot for the period starting at `start`. It is
+// fetched from the api if it hasn't been stored yet, or if `refresh` is set.
+func (m *Module) loadSnapshot(
+	ctx context.Context,
+	options ChannelConfig,
+	start time.Time,
+	end time.Time,
+	refresh bool,
+) (snapshot, error) {
+	var s snapshot
+	periodStart := start.Format(time.DateOnly)
+
+	if !refresh {
+		row, err := m.db.FindLeaderboardSnapshot(ctx, store.FindLeaderboardSnapshotParams{
+			ChannelID:   options.ID,
+			ProjectID:   options.ProjectID,
+			Period:      options.period(),
+			PeriodStart: periodStart,
+		})
+
+		if err == nil {
+			if err := json.Unmarshal([]byte(row.Data), &s); err != nil {
+				return s, fmt.Errorf("could not parse snapshot: %w", err)
+			}
+
+			return s, nil
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return s, fmt.Errorf("error fetching snapshot: %w", err)
+		}
+	}
+
+	s, err := m.fetchSnapshot(options, start, end)
+
+	if err != nil {
+		return s, err
+	}
+
+	data, err := json.Marshal(s)
+
+	if err != nil {
+		return s, err
+	}
+
+	err = m.db.SaveLeaderboardSnapshot(ctx, store.SaveLeaderboardSnapshotParams{
+		ChannelID:   options.ID,
+		ProjectID:   options.ProjectID,
+		Period:      options.period(),
+		PeriodStart: periodStart,
+		Data:        string(data),
+	})
+
+	if err != nil {
+		return s, fmt.Errorf("error saving snapshot: %w", err)
+	}
+
+	return s, nil
+}
+
+func (m *Module) fetchSnapshot(options ChannelConfig, start, end time.Time) (snapshot, error) {
+	var s snapshot
+
+	// d1 and d2 are both inclusive
+	params := func(perPage int) url.Values {
+		return url.Values{
+			"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+			"d1":         {start.Format(time.DateOnly)},
+			"d2":         {end.AddDate(0, 0, -1).Format(time.DateOnly)},
+			"per_page":   {strconv.Itoa(perPage)},
+		}
+	}
+
+	observations, err := m.api.FetchObservations(params(0))
+
+	if err != nil {
+		return s, fmt.Errorf("error fetching observation count: %w", err)
+	}
+
+	time.Sleep(apiRequestDelay)
+	observers, err := m.api.FetchObservers(params(options.size()))
+
+	if err != nil {
+		return s, fmt.Errorf("error fetching top observers: %w", err)
+	}
+
+	time.Sleep(apiRequestDelay)
+	identifiers, err := m.api.FetchIdentifiers(params(options.size()))
+
+	if err != nil {
+		return s, fmt.Errorf("error fetching top identifiers: %w", err)
+	}
+
+	time.Sleep(apiRequestDelay)
+	species, err := m.api.FetchSpeciesCounts(params(options.size()))
+
+	if err != nil {
+		return s, fmt.Errorf("error fetching top species: %w", err)
+	}
+
+	s.Observations = int64(observations.TotalResults)
+	s.Observers = observers.TotalResults
+	s.Species = species.TotalResults
+
+	for _, o := range observers.Results {
+		s.TopObservers = append(s.TopObservers, entry{
+			ID:    o.User.ID,
+			Name:  o.User.DisplayName(),
+			Count: o.ObservationCount,
+		})
+	}
+
+	for _, i := range identifiers.Results {
+		s.TopIdentifiers = append(s.TopIdentifiers, entry{
+			ID:    i.User.ID,
+			Name:  i.User.DisplayName(),
+			Count: i.Count,
+		})
+	}
+
+	for _, c := range species.Results {
+		name := c.Taxon.Name
+
+		if c.Taxon.PreferredCommonName != "" {
+			name = fmt.Sprintf("%s (%s)", c.Taxon.PreferredCommonName, c.Taxon.Name)
+		}
+
+		s.TopSpecies = append(s.TopSpecies, entry{
+			ID:    c.Taxon.ID,
+			Name:  name,
+			Count: c.Count,
+		})
+	}
+
+	return s, nil
+}
@@ -0,0 +1,168 @@
+package milestones
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+// Thresholds decides which counts are worth celebrating. A count is a
+// milestone if it's one of `Milestones`, or a multiple of `Every`.
+type Thresholds struct {
+	Milestones []int64 `json:"milestones,omitempty"`
+	Every      int64   `json:"every,omitempty"`
+}
+
+type ChannelConfig struct {
+	ID           string     `json:"id"`
+	ProjectID    int64      `json:"inat_project_id"`
+	CronPattern  string     `json:"cron_pattern"`
+	Timezone     string     `json:"timezone"`
+	Observations Thresholds `json:"observations"`
+	Species      Thresholds `json:"species"`
+	Observers    Thresholds `json:"observers"`
+	// RareThreshold flags observations of taxa with at most this many
+	// observations worldwide, 0 turns rare sighting alerts off
+	RareThreshold int64 `json:"rare_threshold"`
+	// FirstForProject flags observations of taxa that hadn't been observed in
+	// the project before
+	FirstForProject bool `json:"first_for_project"`
+}
+
+// crossed returns the highest milestone in `(prev, cur]`
+func (t Thresholds) crossed(prev, cur int64) (int64, bool) {
+	var milestone int64
+
+	for _, m := range t.Milestones {
+		if m > prev && m <= cur && m > milestone {
+			milestone = m
+		}
+	}
+
+	if t.Every > 0 {
+		if m := cur - cur%t.Every; m > prev && m > milestone {
+			milestone = m
+		}
+	}
+
+	return milestone, milestone > 0
+}
+
+func parseMilestones(items []string) ([]int64, error) {
+	milestones := make([]int64, 0, len(items))
+
+	for _, item := range items {
+		for _, v := range strings.Split(item, ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid milestone '%s'", v)
+			}
+
+			milestones = append(milestones, n)
+		}
+	}
+
+	return milestones, nil
+}
+
+func validateMilestones(v string) error {
+	_, err := parseMilestones([]string{v})
+	return err
+}
+
+func ConfigCommandOptions() mod.ConfigCommandOptions {
+	args := []*glap.Arg{
+		glap.NewArg("channel-id").Short('c').Required(true).Help("Channel CHANNEL_ID"),
+		glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID"),
+		glap.NewArg("schedule-pattern").
+			Default("*/30 * * * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("How often to check the project, as a cron pattern PATTERN"),
+		glap.NewArg("timezone").
+			Validator(func(v string) error { return mod.ValidateSchedule("@daily", v) }).
+			Help("Timezone the schedule runs in, defaults to UTC TIMEZONE"),
+		glap.NewArg("observation-milestone").
+			Action(glap.Append).
+			Validator(validateMilestones).
+			Help("Celebrate when the project reaches COUNTS observations"),
+		glap.NewArg("observations-every").
+			Default("1000").
+			Help("Celebrate every COUNT observations, 0 to turn off"),
+		glap.NewArg("species-milestone").
+			Action(glap.Append).
+			Validator(validateMilestones).
+			Help("Celebrate when the project reaches COUNTS species"),
+		glap.NewArg("species-every").
+			Default("100").
+			Help("Celebrate every COUNT species, 0 to turn off"),
+		glap.NewArg("observer-milestone").
+			Action(glap.Append).
+			Validator(validateMilestones).
+			Help("Celebrate when the project reaches COUNTS observers"),
+		glap.NewArg("observers-every").
+			Default("25").
+			Help("Celebrate every COUNT observers, 0 to turn off"),
+		glap.NewArg("rare-threshold").
+			Default("10").
+			Help("Flag taxa with at most COUNT observations worldwide, 0 to turn off"),
+		glap.NewArg("first-for-project").
+			Default("true").
+			PossibleValues("true", "false").
+			Help("Flag taxa that are new to the project ENABLED"),
+	}
+
+	return mod.ConfigCommandOptions{
+		Args:       args,
+		KeyArgs:    []string{"channel-id"},
+		ModuleName: moduleName,
+		GetKey: func(m *glap.Matches) string {
+			v, _ := m.GetString("channel-id")
+			return v
+		},
+		GetData: func(m *glap.Matches) any {
+			channelID, _ := m.GetString("channel-id")
+			projectID, _ := m.GetInt64("project-id")
+			cronPattern, _ := m.GetString("schedule-pattern")
+			timezone, _ := m.GetString("timezone")
+			observationMilestones, _ := m.GetStringSlice("observation-milestone")
+			observationsEvery, _ := m.GetInt64("observations-every")
+			speciesMilestones, _ := m.GetStringSlice("species-milestone")
+			speciesEvery, _ := m.GetInt64("species-every")
+			observerMilestones, _ := m.GetStringSlice("observer-milestone")
+			observersEvery, _ := m.GetInt64("observers-every")
+			rareThreshold, _ := m.GetInt64("rare-threshold")
+			firstForProject, _ := m.GetString("first-for-project")
+
+			// milestones have already been checked by the arg validators
+			parsedObservationMilestones, _ := parseMilestones(observationMilestones)
+			parsedSpeciesMilestones, _ := parseMilestones(speciesMilestones)
+			parsedObserverMilestones, _ := parseMilestones(observerMilestones)
+
+			return ChannelConfig{
+				ID:          channelID,
+				ProjectID:   projectID,
+				CronPattern: cronPattern,
+				Timezone:    timezone,
+				Observations: Thresholds{
+					Milestones: parsedObservationMilestones,
+					Every:      observationsEvery,
+				},
+				Species: Thresholds{
+					Milestones: parsedSpeciesMilestones,
+					Every:      speciesEvery,
+				},
+				Observers: Thresholds{
+					Milestones: parsedObserverMilestones,
+					Every:      observersEvery,
+				},
+				RareThreshold:   rareThreshold,
+				FirstForProject: firstForProject == "true",
+			}
+		},
+	}
+}
@@ -0,0 +1,487 @@
+package milestones
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName = "milestones"
+	embedColor = 15844367
+	pageSize   = 200
+	// maxPages caps how many new observations are checked per run, a project
+	// that gets more than that skips ahead to its newest observation
+	maxPages             = 5
+	apiRequestDelay      = time.Second
+	firstForProjectRanks = []string{"species", "hybrid", "subspecies", "variety", "form"}
+)
+
+type Module struct {
+	api        inat.Api
+	db         *store.Queries
+	scheduler  *mod.Scheduler
+	outbox     *mod.Outbox
+	logger     *slog.Logger
+	config     []ChannelConfig
+	configLock sync.RWMutex
+}
+
+// projectStats are the counts milestones are celebrated for
+type projectStats struct {
+	observations int64
+	species      int64
+	observers    int64
+}
+
+func New(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (*Module, error) {
+	return &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		outbox:    outbox,
+		logger:    logger,
+	}, nil
+}
+
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, outbox, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Config() []ChannelConfig {
+	m.configLock.RLock()
+	defer m.configLock.RUnlock()
+	return m.config
+}
+
+func (m *Module) SetConfig(config []ChannelConfig) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	m.config = config
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.logger.Info("started module")
+	m.logger.Info(" -> config", "channels", m.Config())
+	m.scheduleJobs(ctx)
+	return nil
+}
+
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.scheduleJobs(ctx)
+	m.logger.Info(" -> config", "channels", m.Config())
+	return nil
+}
+
+func (m *Module) scheduleJobs(ctx context.Context) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		err := m.scheduler.Register(ctx, mod.Job{
+			Name:     mod.JobName(moduleName, "check", o.ID),
+			Module:   moduleName,
+			Schedule: o.CronPattern,
+			Timezone: o.Timezone,
+			CatchUp:  mod.CatchUpOnce,
+			Run: func(ctx context.Context) error {
+				return m.Check(ctx, o.ID)
+			},
+		})
+
+		if err != nil {
+			m.logger.Error("error scheduling milestone check", "channel", o.ID, "err", err)
+		}
+	}
+}
+
+func (m *Module) channelConfig(channelID string) (ChannelConfig, error) {
+	for _, o := range m.Config() {
+		if o.ID == channelID {
+			return o, nil
+		}
+	}
+
+	return ChannelConfig{}, errors.New("channel config not found")
+}
+
+// Check posts a celebration for every milestone the project has passed since
+// the last check, and an alert for every new observation that is a first for
+// the project or a rare sighting. The first check only records where the
+// project is at.
+func (m *Module) Check(ctx context.Context, channelID string) error {
+	options, err := m.channelConfig(channelID)
+
+	if err != nil {
+		return err
+	}
+
+	stats, err := m.fetchStats(options.ProjectID)
+
+	if err != nil {
+		return err
+	}
+
+	state, err := m.db.FindProjectMilestone(ctx, store.FindProjectMilestoneParams{
+		ChannelID: channelID,
+		ProjectID: options.ProjectID,
+	})
+
+	if errors.Is(err, sql.ErrNoRows) {
+		lastID, err := m.newestObservationID(options.ProjectID)
+
+		if err != nil {
+			return err
+		}
+
+		m.logger.Info("starting milestone checks", "channel", channelID, "project", options.ProjectID)
+		return m.saveState(ctx, options, stats, lastID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error fetching milestone state: %w", err)
+	}
+
+	milestones := []struct {
+		thresholds Thresholds
+		prev       int64
+		cur        int64
+		noun       string
+	}{
+		{options.Observations, state.ObservationCount, stats.observations, "observations"},
+		{options.Species, state.SpeciesCount, stats.species, "species"},
+		{options.Observers, state.ObserverCount, stats.observers, "observers"},
+	}
+
+	for _, ms := range milestones {
+		milestone, ok := ms.thresholds.crossed(ms.prev, ms.cur)
+
+		if !ok {
+			continue
+		}
+
+		if err := m.celebrate(ctx, options, milestone, ms.noun); err != nil {
+			return err
+		}
+	}
+
+	lastID := state.LastObservationID
+
+	if options.FirstForProject || options.RareThreshold > 0 {
+		lastID, err = m.checkNewObservations(ctx, options, lastID)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return m.saveState(ctx, options, stats, lastID)
+}
+
+func (m *Module) fetchStats(projectID int64) (projectStats, error) {
+	var stats projectStats
+	params := url.Values{
+		"project_id": {strconv.FormatInt(projectID, 10)},
+		"per_page":   {"0"},
+	}
+
+	observations, err := m.api.FetchObservations(params)
+
+	if err != nil {
+		return stats, fmt.Errorf("error fetching observation count: %w", err)
+	}
+
+	time.Sleep(apiRequestDelay)
+	species, err := m.api.FetchSpeciesCounts(params)
+
+	if err != nil {
+		return stats, fmt.Errorf("error fetching species count: %w", err)
+	}
+
+	time.Sleep(apiRequestDelay)
+	observers, err := m.api.FetchObservers(params)
+
+	if err != nil {
+		return stats, fmt.Errorf("error fetching observer count: %w", err)
+	}
+
+	stats.observations = int64(observations.TotalResults)
+	stats.species = species.TotalResults
+	stats.observers = observers.TotalResults
+	return stats, nil
+}
+
+func (m *Module) newestObservationID(projectID int64) (int64, error) {
+	r, err := m.api.FetchObservations(url.Values{
+		"project_id": {strconv.FormatInt(projectID, 10)},
+		"order":      {"desc"},
+		"order_by":   {"id"},
+		"per_page":   {"1"},
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("error fetching newest observation: %w", err)
+	}
+
+	if len(r.Results) <= 0 {
+		return 0, nil
+	}
+
+	return r.Results[0].ID, nil
+}
+
+func (m *Module) saveState(
+	ctx context.Context,
+	options ChannelConfig,
+	stats projectStats,
+	lastID int64,
+) error {
+	err := m.db.SaveProjectMilestone(ctx, store.SaveProjectMilestoneParams{
+		ChannelID:         options.ID,
+		ProjectID:         options.ProjectID,
+		ObservationCount:  stats.observations,
+		SpeciesCount:      stats.species,
+		ObserverCount:     stats.observers,
+		LastObservationID: lastID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving milestone state: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Module) celebrate(
+	ctx context.Context,
+	options ChannelConfig,
+	milestone int64,
+	noun string,
+) error {
+	projectURL := fmt.Sprintf("https://inaturalist.org/projects/%d", options.ProjectID)
+	title := "Our project"
+	embed := &discordgo.MessageEmbed{
+		URL:   projectURL,
+		Color: embedColor,
+	}
+
+	if project, err := m.api.FetchProject(options.ProjectID); err != nil {
+		m.logger.Warn("error fetching project", "project", options.ProjectID, "err", err)
+	} else {
+		title = project.Title
+
+		if project.IconURL != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: project.IconURL}
+		}
+	}
+
+	embed.Title = fmt.Sprintf(":tada: %s just reached %s %s!", title, mod.FormatCount(milestone), noun)
+	embed.Description = "Thank you to everyone who made it happen, keep it up!"
+
+	_, err := m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      moduleName,
+		ChannelID: options.ID,
+		DedupeKey: fmt.Sprintf("%s:%s:%d:%s:%d", moduleName, options.ID, options.ProjectID, noun, milestone),
+		Message:   mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing milestone: %w", err)
+	}
+
+	m.logger.Info("celebrating milestone", "channel", options.ID, "milestone", milestone, "of", noun)
+	return nil
+}
+
+// checkNewObservations alerts on the observations added since `lastID`, and
+// returns the id of the newest one that was checked
+func (m *Module) checkNewObservations(
+	ctx context.Context,
+	options ChannelConfig,
+	lastID int64,
+) (int64, error) {
+	for range maxPages {
+		time.Sleep(apiRequestDelay)
+		r, err := m.api.FetchObservations(url.Values{
+			"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+			"id_above":   {strconv.FormatInt(lastID, 10)},
+			"order":      {"asc"},
+			"order_by":   {"id"},
+			"per_page":   {strconv.Itoa(pageSize)},
+		})
+
+		if err != nil {
+			return lastID, fmt.Errorf("error fetching new observations: %w", err)
+		}
+
+		for _, o := range r.Results {
+			if o.Taxon.ID != 0 {
+				if err := m.checkObservation(ctx, options, o); err != nil {
+					return lastID, err
+				}
+			}
+
+			lastID = o.ID
+		}
+
+		if len(r.Results) < pageSize {
+			return lastID, nil
+		}
+	}
+
+	// falling further behind on every run would never catch up
+	newestID, err := m.newestObservationID(options.ProjectID)
+
+	if err != nil {
+		return lastID, err
+	}
+
+	m.logger.Warn(
+		"too many new observations to check, skipping to the newest",
+		"channel",
+		options.ID,
+		"project",
+		options.ProjectID,
+		"from",
+		lastID,
+		"to",
+		newestID,
+	)
+
+	return max(lastID, newestID), nil
+}
+
+func (m *Module) checkObservation(ctx context.Context, options ChannelConfig, o inat.Observation) error {
+	rare := options.RareThreshold > 0 &&
+		o.Taxon.ObservationsCount > 0 &&
+		o.Taxon.ObservationsCount <= options.RareThreshold
+	first := false
+
+	if options.FirstForProject && slices.Contains(firstForProjectRanks, o.Taxon.Rank) {
+		time.Sleep(apiRequestDelay)
+		r, err := m.api.FetchObservations(url.Values{
+			"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+			"taxon_id":   {strconv.FormatInt(o.Taxon.ID, 10)},
+			"id_below":   {strconv.FormatInt(o.ID, 10)},
+			"per_page":   {"0"},
+		})
+
+		if err != nil {
+			return fmt.Errorf("error checking observation %d: %w", o.ID, err)
+		}
+
+		first = r.TotalResults == 0
+	}
+
+	if !rare && !first {
+		return nil
+	}
+
+	return m.alert(ctx, options, o, first, rare)
+}
+
+func (m *Module) alert(
+	ctx context.Context,
+	options ChannelConfig,
+	o inat.Observation,
+	first bool,
+	rare bool,
+) error {
+	title := ":star2: A first for the project!"
+
+	if rare && first {
+		title = ":star2: A first for the project, and a rare sighting!"
+	} else if rare {
+		title = ":gem: A rare sighting!"
+	}
+
+	taxonName, commonName := o.TaxonNames()
+	embed := &discordgo.MessageEmbed{
+		URL:   fmt.Sprintf("https://inaturalist.org/observations/%d", o.ID),
+		Title: title,
+		Description: fmt.Sprintf(
+			"%s observed **%s** (%s)",
+			o.User.DisplayName(),
+			taxonName,
+			commonName,
+		),
+		Color: embedColor,
+		Fields: []*discordgo.MessageEmbedField{{
+			Name:   "Observations worldwide",
+			Value:  mod.FormatCount(o.Taxon.ObservationsCount),
+			Inline: true,
+		}},
+	}
+
+	// photos with all rights reserved aren't shown
+	if photos := o.LicensedPhotos(); len(photos) > 0 {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: photos[0].Medium()}
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: photos[0].Attribution}
+	}
+
+	_, err := m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      moduleName,
+		ChannelID: options.ID,
+		DedupeKey: fmt.Sprintf("%s:%s:observation:%d", moduleName, options.ID, o.ID),
+		Message:   mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing alert: %w", err)
+	}
+
+	m.logger.Info("alerting on observation", "channel", options.ID, "id", o.ID, "first", first, "rare", rare)
+	return nil
+}
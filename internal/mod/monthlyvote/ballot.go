@@ -0,0 +1,198 @@
+package monthlyvote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	// discord limits select option labels to 100 characters
+	maxOptionLength = 100
+)
+
+func (m *Module) registerHandlers(discord *discordgo.Session) {
+	m.handlersRegistered = true
+
+	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type != discordgo.InteractionMessageComponent {
+			return
+		}
+
+		module, action, args, ok := mod.ParseComponentID(i.MessageComponentData().CustomID)
+
+		if !ok || module != moduleName {
+			return
+		}
+
+		if action != "vote" || len(args) <= 0 {
+			mod.RespondEphemeral(d, i, "Sorry, I don't remember that vote anymore.")
+			return
+		}
+
+		m.handleVote(d, i, args[0])
+	})
+}
+
+// handleVote records a member's vote. Members get one vote per ballot, voting
+// again changes it.
+func (m *Module) handleVote(d *discordgo.Session, i *discordgo.InteractionCreate, arg string) {
+	ctx := context.Background()
+	ballotID, err := strconv.ParseInt(arg, 10, 64)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "Sorry, I don't remember that vote anymore.")
+		return
+	}
+
+	ballot, err := m.db.FindBallotByID(ctx, ballotID)
+
+	if err != nil {
+		m.logger.Error("error fetching ballot", "ballot", ballotID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, I don't remember that vote anymore.")
+		return
+	}
+
+	if ballot.ClosedAt.Valid {
+		mod.RespondEphemeral(d, i, "Sorry, voting has closed.")
+		return
+	}
+
+	values := i.MessageComponentData().Values
+
+	if len(values) <= 0 {
+		mod.RespondEphemeral(d, i, "Pick an observation to vote for.")
+		return
+	}
+
+	observationID, err := strconv.ParseInt(values[0], 10, 64)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "Sorry, that isn't one of the observations in this vote.")
+		return
+	}
+
+	var candidates []candidate
+
+	if err := json.Unmarshal([]byte(ballot.Candidates), &candidates); err != nil {
+		m.logger.Error("could not parse ballot candidates", "ballot", ballotID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	idx := slices.IndexFunc(candidates, func(c candidate) bool { return c.ID == observationID })
+
+	if idx < 0 {
+		mod.RespondEphemeral(d, i, "Sorry, that isn't one of the observations in this vote.")
+		return
+	}
+
+	user := mod.InteractionUser(i)
+	err = m.db.SaveBallotVote(ctx, store.SaveBallotVoteParams{
+		BallotID:      ballotID,
+		DiscordUserID: user.ID,
+		ObservationID: observationID,
+	})
+
+	if err != nil {
+		m.logger.Error("error saving vote", "ballot", ballotID, "user", user.ID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	mod.RespondEphemeral(d, i, fmt.Sprintf(
+		"Your vote for %s by %s has been counted! You can change it until voting closes.",
+		candidates[idx].Taxon,
+		candidates[idx].Observer,
+	))
+}
+
+func ballotMessage(ballotID int64, month time.Time, candidates []candidate) mod.OutboxMessage {
+	lines := make([]string, 0, len(candidates))
+	options := make([]discordgo.SelectMenuOption, 0, len(candidates))
+
+	for n, c := range candidates {
+		lines = append(lines, fmt.Sprintf(
+			"%d. [%s](https://inaturalist.org/observations/%d) by %s",
+			n+1,
+			c.Taxon,
+			c.ID,
+			c.Observer,
+		))
+
+		label := fmt.Sprintf("%d. %s by %s", n+1, c.Taxon, c.Observer)
+
+		if r := []rune(label); len(r) > maxOptionLength {
+			label = string(r[:maxOptionLength])
+		}
+
+		options = append(options, discordgo.SelectMenuOption{
+			Label: label,
+			Value: strconv.FormatInt(c.ID, 10),
+		})
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf(":ballot_box: Vote for %s's observation of the month!", month.Format("January")),
+		Description: strings.Join(lines, "\n"),
+		Color:       embedColor,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Pick your favourite below, you can change your vote until voting closes"},
+	}
+
+	if idx := slices.IndexFunc(candidates, func(c candidate) bool { return c.PhotoURL != "" }); idx >= 0 {
+		embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: candidates[idx].PhotoURL}
+	}
+
+	return mod.OutboxMessage{
+		Embeds: []*discordgo.MessageEmbed{embed},
+		Components: []discordgo.ActionsRow{{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					MenuType:    discordgo.StringSelectMenu,
+					CustomID:    mod.ComponentID(moduleName, "vote", strconv.FormatInt(ballotID, 10)),
+					Placeholder: "Vote for an observation",
+					Options:     options,
+				},
+			},
+		}},
+	}
+}
+
+func winnerMessage(month time.Time, c candidate, votes int64) mod.OutboxMessage {
+	noun := "votes"
+
+	if votes == 1 {
+		noun = "vote"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		URL:   fmt.Sprintf("https://inaturalist.org/observations/%d", c.ID),
+		Title: fmt.Sprintf(":trophy: %s's observation of the month: %s!", month.Format("January"), c.Taxon),
+		Description: fmt.Sprintf(
+			"Congratulations to **%s**, who won with %d %s!",
+			c.Observer,
+			votes,
+			noun,
+		),
+		Color: embedColor,
+	}
+
+	if c.PhotoURL != "" {
+		embed.Image = &discordgo.MessageEmbedImage{URL: c.PhotoURL}
+	}
+
+	if c.Attribution != "" {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: c.Attribution}
+	}
+
+	return mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{embed}}
+}
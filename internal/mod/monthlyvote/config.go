@@ -0,0 +1,70 @@
+package monthlyvote
+
+import (
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+// ChannelConfig runs a monthly vote in a channel, for the observations inatobs
+// posted to a source channel during the month before
+type ChannelConfig struct {
+	ID               string `json:"id"`
+	SourceChannelID  string `json:"source_channel_id"`
+	OpenCronPattern  string `json:"open_cron_pattern"`
+	CloseCronPattern string `json:"close_cron_pattern"`
+	Timezone         string `json:"timezone"`
+}
+
+func (c ChannelConfig) sourceChannelID() string {
+	if c.SourceChannelID == "" {
+		return c.ID
+	}
+
+	return c.SourceChannelID
+}
+
+func ConfigCommandOptions() mod.ConfigCommandOptions {
+	args := []*glap.Arg{
+		glap.NewArg("channel-id").Short('c').Required(true).Help("Channel to post the ballot in CHANNEL_ID"),
+		glap.NewArg("source-channel-id").
+			Short('s').
+			Help("Channel inatobs posts to, defaults to the ballot channel CHANNEL_ID"),
+		glap.NewArg("open-pattern").
+			Default("0 12 1 * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("When to post the ballot for the month before PATTERN"),
+		glap.NewArg("close-pattern").
+			Default("0 12 8 * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("When to close the vote and announce the winner PATTERN"),
+		glap.NewArg("timezone").
+			Validator(func(v string) error { return mod.ValidateSchedule("@daily", v) }).
+			Help("Timezone months start in, defaults to UTC TIMEZONE"),
+	}
+
+	return mod.ConfigCommandOptions{
+		Args:       args,
+		KeyArgs:    []string{"channel-id"},
+		ModuleName: moduleName,
+		GetKey: func(m *glap.Matches) string {
+			v, _ := m.GetString("channel-id")
+			return v
+		},
+		GetData: func(m *glap.Matches) any {
+			channelID, _ := m.GetString("channel-id")
+			sourceChannelID, _ := m.GetString("source-channel-id")
+			openPattern, _ := m.GetString("open-pattern")
+			closePattern, _ := m.GetString("close-pattern")
+			timezone, _ := m.GetString("timezone")
+
+			return ChannelConfig{
+				ID:               channelID,
+				SourceChannelID:  sourceChannelID,
+				OpenCronPattern:  openPattern,
+				CloseCronPattern: closePattern,
+				Timezone:         timezone,
+			}
+		},
+	}
+}
@@ -0,0 +1,435 @@
+package monthlyvote
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName = "monthlyvote"
+	ballotKind = moduleName + ":ballot"
+	embedColor = 15844367
+	// discord select menus can't have more than 25 options, the most faved
+	// observations make the ballot
+	maxCandidates = 25
+)
+
+// Module runs a monthly community vote for the best observation inatobs
+// posted. Ballots and votes are stored, so a restart in the middle of a vote
+// doesn't lose anything.
+type Module struct {
+	api                inat.Api
+	db                 *store.Queries
+	scheduler          *mod.Scheduler
+	outbox             *mod.Outbox
+	logger             *slog.Logger
+	config             []ChannelConfig
+	handlersRegistered bool
+	configLock         sync.RWMutex
+}
+
+// candidate is an observation on a ballot
+type candidate struct {
+	Taxon       string `json:"taxon"`
+	Observer    string `json:"observer"`
+	PhotoURL    string `json:"photo_url"`
+	Attribution string `json:"attribution"`
+	ID          int64  `json:"id"`
+}
+
+// ballotMeta identifies the ballot a queued post is for
+type ballotMeta struct {
+	BallotID int64 `json:"ballot_id"`
+}
+
+func New(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (*Module, error) {
+	m := &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		outbox:    outbox,
+		logger:    logger,
+	}
+
+	outbox.OnDelivered(ballotKind, m.saveBallotMessage)
+	return m, nil
+}
+
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, outbox, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Config() []ChannelConfig {
+	m.configLock.RLock()
+	defer m.configLock.RUnlock()
+	return m.config
+}
+
+func (m *Module) SetConfig(config []ChannelConfig) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	m.config = config
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+
+	if !m.handlersRegistered {
+		m.registerHandlers(discord)
+	}
+
+	m.logger.Info("started module")
+	m.logger.Info(" -> config", "channels", m.Config())
+	m.scheduleJobs(ctx, discord)
+	return nil
+}
+
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	config, err := mod.FetchModuleConfiguration[ChannelConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.scheduleJobs(ctx, discord)
+	m.logger.Info(" -> config", "channels", m.Config())
+	return nil
+}
+
+func (m *Module) scheduleJobs(ctx context.Context, discord *discordgo.Session) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		jobs := []mod.Job{
+			{
+				Name:     mod.JobName(moduleName, "open", o.ID),
+				Schedule: o.OpenCronPattern,
+				Run: func(ctx context.Context) error {
+					return m.Open(ctx, o.ID)
+				},
+			},
+			{
+				Name:     mod.JobName(moduleName, "close", o.ID),
+				Schedule: o.CloseCronPattern,
+				Run: func(ctx context.Context) error {
+					return m.Close(ctx, discord, o.ID)
+				},
+			},
+		}
+
+		for _, job := range jobs {
+			job.Module = moduleName
+			job.Timezone = o.Timezone
+			job.CatchUp = mod.CatchUpOnce
+
+			if err := m.scheduler.Register(ctx, job); err != nil {
+				m.logger.Error("error scheduling vote", "channel", o.ID, "job", job.Name, "err", err)
+			}
+		}
+	}
+}
+
+func (m *Module) channelConfig(channelID string) (ChannelConfig, error) {
+	for _, o := range m.Config() {
+		if o.ID == channelID {
+			return o, nil
+		}
+	}
+
+	return ChannelConfig{}, errors.New("channel config not found")
+}
+
+// Open posts the ballot for the month before, with every observation that was
+// posted to the source channel during it. Each month only gets one ballot.
+func (m *Module) Open(ctx context.Context, channelID string) error {
+	options, err := m.channelConfig(channelID)
+
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(options.Timezone)
+
+	if err != nil {
+		return fmt.Errorf("invalid timezone '%s': %w", options.Timezone, err)
+	}
+
+	now := time.Now().In(loc)
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	start := end.AddDate(0, -1, 0)
+	period := start.Format("2006-01")
+
+	posted, err := m.db.ListPostedObservations(ctx, store.ListPostedObservationsParams{
+		ChannelID: options.sourceChannelID(),
+		Start:     start.UTC(),
+		End:       end.UTC(),
+	})
+
+	if err != nil {
+		return fmt.Errorf("error fetching posted observations: %w", err)
+	}
+
+	if len(posted) <= 0 {
+		m.logger.Info("no observations were posted, skipping vote", "channel", channelID, "period", period)
+		return nil
+	}
+
+	candidates, err := m.fetchCandidates(posted)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(candidates)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.CreateBallot(ctx, store.CreateBallotParams{
+		ChannelID:  channelID,
+		Period:     period,
+		Candidates: string(data),
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving ballot: %w", err)
+	}
+
+	ballot, err := m.db.FindBallot(ctx, store.FindBallotParams{ChannelID: channelID, Period: period})
+
+	if err != nil {
+		return fmt.Errorf("error fetching ballot: %w", err)
+	}
+
+	// the ballot might have been created by an earlier run that failed to
+	// queue it, so always use the stored candidates
+	if err := json.Unmarshal([]byte(ballot.Candidates), &candidates); err != nil {
+		return fmt.Errorf("could not parse ballot candidates: %w", err)
+	}
+
+	_, err = m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      ballotKind,
+		ChannelID: channelID,
+		DedupeKey: fmt.Sprintf("%s:%s:%s", ballotKind, channelID, period),
+		Meta:      ballotMeta{BallotID: ballot.ID},
+		Message:   ballotMessage(ballot.ID, start, candidates),
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing ballot: %w", err)
+	}
+
+	m.logger.Info("opened vote", "channel", channelID, "period", period, "candidates", len(candidates))
+	return nil
+}
+
+// fetchCandidates looks up the posted observations. If there are more than
+// fit on a ballot, the most faved ones are picked.
+func (m *Module) fetchCandidates(posted []store.SeenObservation) ([]candidate, error) {
+	var observations []inat.Observation
+	ids := make([]string, 0, len(posted))
+
+	for _, p := range posted {
+		if !slices.Contains(ids, strconv.FormatInt(p.ID, 10)) {
+			ids = append(ids, strconv.FormatInt(p.ID, 10))
+		}
+	}
+
+	for chunk := range slices.Chunk(ids, 100) {
+		r, err := m.api.FetchObservations(url.Values{
+			"id":       {strings.Join(chunk, ",")},
+			"per_page": {"100"},
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error fetching observations: %w", err)
+		}
+
+		observations = append(observations, r.Results...)
+	}
+
+	if len(observations) <= 0 {
+		return nil, errors.New("none of the posted observations could be found")
+	}
+
+	slices.SortStableFunc(observations, func(a, b inat.Observation) int {
+		return cmp.Compare(b.FavesCount, a.FavesCount)
+	})
+
+	observations = observations[:min(len(observations), maxCandidates)]
+	slices.SortFunc(observations, func(a, b inat.Observation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	candidates := make([]candidate, 0, len(observations))
+
+	for _, o := range observations {
+		c := candidate{
+			ID:       o.ID,
+			Taxon:    o.Taxon.Name,
+			Observer: o.User.DisplayName(),
+		}
+
+		if o.Taxon.PreferredCommonName != "" {
+			c.Taxon = fmt.Sprintf("%s (%s)", o.Taxon.Name, o.Taxon.PreferredCommonName)
+		}
+
+		if c.Taxon == "" {
+			c.Taxon = "Unknown"
+		}
+
+		// photos with all rights reserved are only linked to
+		if photos := o.LicensedPhotos(); len(photos) > 0 {
+			c.PhotoURL = photos[0].Medium()
+			c.Attribution = photos[0].Attribution
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// Close counts the votes on the channel's open ballot and announces the
+// winner
+func (m *Module) Close(ctx context.Context, discord *discordgo.Session, channelID string) error {
+	ballot, err := m.db.FindOpenBallot(ctx, channelID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error fetching open ballot: %w", err)
+	}
+
+	var candidates []candidate
+
+	if err := json.Unmarshal([]byte(ballot.Candidates), &candidates); err != nil {
+		return fmt.Errorf("could not parse ballot candidates: %w", err)
+	}
+
+	votes, err := m.db.CountBallotVotes(ctx, ballot.ID)
+
+	if err != nil {
+		return fmt.Errorf("error counting votes: %w", err)
+	}
+
+	month, _ := time.Parse("2006-01", ballot.Period)
+	message := mod.OutboxMessage{
+		Content: fmt.Sprintf("Nobody voted for %s's observation of the month.", month.Format("January")),
+	}
+
+	if len(votes) > 0 {
+		idx := slices.IndexFunc(candidates, func(c candidate) bool {
+			return c.ID == votes[0].ObservationID
+		})
+
+		if idx >= 0 {
+			message = winnerMessage(month, candidates[idx], votes[0].Votes)
+		}
+	}
+
+	_, err = m.outbox.Enqueue(ctx, mod.Delivery{
+		Module:    moduleName,
+		Kind:      moduleName,
+		ChannelID: channelID,
+		DedupeKey: fmt.Sprintf("%s:winner:%d", moduleName, ballot.ID),
+		Message:   message,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error queueing winner: %w", err)
+	}
+
+	if err := m.db.CloseBallot(ctx, ballot.ID); err != nil {
+		return fmt.Errorf("error closing ballot: %w", err)
+	}
+
+	if ballot.MessageID != "" {
+		// voting is closed either way, this only takes the menu away
+		_, err := discord.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         ballot.MessageID,
+			Channel:    channelID,
+			Components: &[]discordgo.MessageComponent{},
+		})
+
+		if err != nil {
+			m.logger.Warn("error removing ballot menu", "ballot", ballot.ID, "err", err)
+		}
+	}
+
+	m.logger.Info("closed vote", "channel", channelID, "period", ballot.Period)
+	return nil
+}
+
+// saveBallotMessage records which message a ballot was posted as, so its
+// menu can be taken away once voting closes
+func (m *Module) saveBallotMessage(ctx context.Context, data []byte, sent *discordgo.Message) error {
+	var meta ballotMeta
+
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("could not parse ballot meta: %w", err)
+	}
+
+	err := m.db.SaveBallotMessage(ctx, store.SaveBallotMessageParams{
+		MessageID: sent.ID,
+		ID:        meta.BallotID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving ballot message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,400 @@
+package mod
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.uber.org/fx"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+const (
+	outboxPending   = "pending"
+	outboxDelivered = "delivered"
+	outboxFailed    = "failed"
+)
+
+var (
+	outboxPollInterval = 5 * time.Second
+	outboxBatchSize    = 10
+	outboxMaxAttempts  = 10
+	outboxBaseBackoff  = 30 * time.Second
+	outboxMaxBackoff   = time.Hour
+	outboxRetention    = 7 * 24 * time.Hour
+	outboxHTTPClient   = &http.Client{Timeout: 30 * time.Second}
+)
+
+// OutboxFile is an attachment that is downloaded from `URL` when the message
+// is sent. If `ContentType` is empty, the content type of the download is
+// used.
+type OutboxFile struct {
+	URL         string `json:"url"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+}
+
+// OutboxMessage is a message that is stored until discord confirms it has
+// been delivered
+type OutboxMessage struct {
+	Content    string                    `json:"content,omitempty"`
+	Embeds     []*discordgo.MessageEmbed `json:"embeds,omitempty"`
+	Files      []OutboxFile              `json:"files,omitempty"`
+	Components []discordgo.ActionsRow    `json:"components,omitempty"`
+}
+
+// Delivery is a message to be sent to a channel. `Meta` is stored alongside
+// it and handed back to the kind's `DeliveredFunc` once the message has been
+// sent. Deliveries with the same non-empty `DedupeKey` are only queued once,
+// unless the earlier one failed.
+type Delivery struct {
+	Meta      any
+	Module    string
+	Kind      string
+	ChannelID string
+	DedupeKey string
+	Message   OutboxMessage
+}
+
+// DeliveredFunc is called after a message of a given kind has been sent
+type DeliveredFunc func(ctx context.Context, meta []byte, sent *discordgo.Message) error
+
+// Outbox is a durable queue of outgoing discord messages. Messages are stored
+// in the database and retried with backoff until they are delivered, so a
+// discord outage or restart doesn't lose them.
+type Outbox struct {
+	db           *store.Queries
+	logger       *slog.Logger
+	discord      *discordgo.Session
+	handlers     map[string]DeliveredFunc
+	wake         chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+	handlersLock sync.RWMutex
+	startOnce    sync.Once
+}
+
+func NewOutbox(db *store.Queries, logger *slog.Logger) (*Outbox, error) {
+	return &Outbox{
+		db:       db,
+		logger:   logger,
+		handlers: make(map[string]DeliveredFunc),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func OutboxProvider(
+	lc fx.Lifecycle,
+	db *store.Queries,
+	logger *slog.Logger,
+) (*Outbox, error) {
+	o, err := NewOutbox(db, logger.With("mod", "outbox"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			o.logger.Info("stopping outbox...")
+			close(o.stop)
+
+			// if the worker was never started, there is nothing to wait for
+			o.startOnce.Do(func() { close(o.done) })
+
+			select {
+			case <-o.done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	return o, nil
+}
+
+// Start begins delivering queued messages. It is safe to call more than
+// once, only the first call has any effect.
+func (o *Outbox) Start(discord *discordgo.Session) {
+	o.startOnce.Do(func() {
+		o.discord = discord
+		go o.work()
+	})
+}
+
+// OnDelivered registers the function that is called when a message of the
+// given kind has been sent
+func (o *Outbox) OnDelivered(kind string, f DeliveredFunc) {
+	o.handlersLock.Lock()
+	defer o.handlersLock.Unlock()
+	o.handlers[kind] = f
+}
+
+// Enqueue stores a message to be sent. It returns false if a message with
+// the same dedupe key has already been queued.
+func (o *Outbox) Enqueue(ctx context.Context, d Delivery) (bool, error) {
+	payload, err := json.Marshal(d.Message)
+
+	if err != nil {
+		return false, fmt.Errorf("error encoding message: %w", err)
+	}
+
+	meta, err := json.Marshal(d.Meta)
+
+	if err != nil {
+		return false, fmt.Errorf("error encoding message meta: %w", err)
+	}
+
+	count, err := o.db.CreateOutboxMessage(ctx, store.CreateOutboxMessageParams{
+		Module:        d.Module,
+		Kind:          d.Kind,
+		ChannelID:     d.ChannelID,
+		DedupeKey:     d.DedupeKey,
+		Payload:       string(payload),
+		Meta:          string(meta),
+		NextAttemptAt: time.Now().UTC(),
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("error queueing message: %w", err)
+	}
+
+	if count <= 0 {
+		return false, nil
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return true, nil
+}
+
+func (o *Outbox) work() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		o.deliverDue()
+
+		select {
+		case <-o.stop:
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+func (o *Outbox) deliverDue() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	messages, err := o.db.FindDueOutboxMessages(ctx, store.FindDueOutboxMessagesParams{
+		Now:   now,
+		Limit: int64(outboxBatchSize),
+	})
+
+	if err != nil {
+		o.logger.Error("error fetching queued messages", "err", err)
+		return
+	}
+
+	for _, msg := range messages {
+		o.deliver(ctx, msg)
+	}
+
+	err = o.db.DeleteDeliveredOutboxMessages(ctx, now.Add(-outboxRetention))
+
+	if err != nil {
+		o.logger.Error("error pruning delivered messages", "err", err)
+	}
+}
+
+// deliver sends a queued message. If the process dies between the message
+// being sent and it being marked as delivered, it will be sent again on the
+// next start; that is preferable to losing it.
+func (o *Outbox) deliver(ctx context.Context, msg store.OutboxMessage) {
+	logger := o.logger.With("id", msg.ID, "kind", msg.Kind, "channel", msg.ChannelID)
+	attempts := msg.Attempts + 1
+	sent, err := o.send(msg)
+
+	if err != nil {
+		status := outboxPending
+		dedupeKey := msg.DedupeKey
+		nextAttemptAt := time.Now().UTC().Add(backoff(attempts))
+
+		if attempts >= int64(outboxMaxAttempts) || isPermanentError(err) {
+			// release the dedupe key, so the message can be queued again
+			status = outboxFailed
+			dedupeKey = ""
+			logger.Error("giving up on message", "attempts", attempts, "err", err)
+		} else {
+			logger.Warn("error sending message, will retry", "attempts", attempts, "retry_at", nextAttemptAt, "err", err)
+		}
+
+		o.updateAttempt(ctx, msg, store.UpdateOutboxMessageAttemptParams{
+			Status:        status,
+			Attempts:      attempts,
+			NextAttemptAt: nextAttemptAt,
+			LastError:     err.Error(),
+			DedupeKey:     dedupeKey,
+		})
+		return
+	}
+
+	o.updateAttempt(ctx, msg, store.UpdateOutboxMessageAttemptParams{
+		Status:        outboxDelivered,
+		Attempts:      attempts,
+		NextAttemptAt: msg.NextAttemptAt,
+		MessageID:     sent.ID,
+		DedupeKey:     msg.DedupeKey,
+	})
+
+	o.handlersLock.RLock()
+	handler, ok := o.handlers[msg.Kind]
+	o.handlersLock.RUnlock()
+
+	// not every kind of message needs to do anything once it's delivered
+	if !ok {
+		return
+	}
+
+	if err := handler(ctx, []byte(msg.Meta), sent); err != nil {
+		logger.Error("error handling delivered message", "err", err)
+	}
+}
+
+func (o *Outbox) send(msg store.OutboxMessage) (*discordgo.Message, error) {
+	var payload OutboxMessage
+
+	if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("could not parse queued message: %w", err)
+	}
+
+	files := make([]*discordgo.File, 0, len(payload.Files))
+
+	// a file that can't be downloaded fails the send, so it's retried rather
+	// than posted without it
+	for _, f := range payload.Files {
+		file, err := downloadFile(f)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve data for file %s: %w", f.URL, err)
+		}
+
+		files = append(files, file)
+	}
+
+	components := make([]discordgo.MessageComponent, 0, len(payload.Components))
+
+	for _, row := range payload.Components {
+		components = append(components, row)
+	}
+
+	return o.discord.ChannelMessageSendComplex(msg.ChannelID, &discordgo.MessageSend{
+		Content:    payload.Content,
+		Embeds:     payload.Embeds,
+		Files:      files,
+		Components: components,
+	})
+}
+
+func (o *Outbox) updateAttempt(
+	ctx context.Context,
+	msg store.OutboxMessage,
+	params store.UpdateOutboxMessageAttemptParams,
+) {
+	params.ID = msg.ID
+	params.UpdatedAt = time.Now().UTC()
+
+	if err := o.db.UpdateOutboxMessageAttempt(ctx, params); err != nil {
+		o.logger.Error("error saving message status", "id", msg.ID, "err", err)
+	}
+}
+
+// backoff doubles the wait after each failed attempt, up to
+// `outboxMaxBackoff`
+func backoff(attempts int64) time.Duration {
+	d := outboxBaseBackoff
+
+	for range attempts - 1 {
+		d *= 2
+
+		if d >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+
+	return d
+}
+
+// isPermanentError reports whether retrying a send can't succeed, for example
+// because the channel was deleted or the bot can't post in it
+// downloadFile fetches a file to attach to a message
+func downloadFile(f OutboxFile) (*discordgo.File, error) {
+	res, err := outboxHTTPClient.Get(f.URL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", res.StatusCode)
+	}
+
+	data, err := io.ReadAll(res.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := f.ContentType
+
+	if contentType == "" {
+		contentType = res.Header.Get("Content-Type")
+	}
+
+	return &discordgo.File{
+		Name:        f.Name,
+		ContentType: contentType,
+		Reader:      bytes.NewReader(data),
+	}, nil
+}
+
+func isPermanentError(err error) bool {
+	var restErr *discordgo.RESTError
+
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return false
+	}
+
+	switch restErr.Response.StatusCode {
+	case http.StatusBadRequest,
+		http.StatusUnauthorized,
+		http.StatusForbidden,
+		http.StatusNotFound:
+		return true
+	}
+
+	// anything else, like a rate limit or attachments that were too large
+	// this time, may work on a later attempt
+	return false
+}
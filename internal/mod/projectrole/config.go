@@ -0,0 +1,51 @@
+package projectrole
+
+import (
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+// GuildConfig gives linked members of an iNaturalist project a role in a
+// guild
+type GuildConfig struct {
+	ID          string `json:"id"`
+	ProjectID   int64  `json:"inat_project_id"`
+	RoleID      string `json:"role_id"`
+	CronPattern string `json:"cron_pattern"`
+}
+
+func ConfigCommandOptions() mod.ConfigCommandOptions {
+	args := []*glap.Arg{
+		glap.NewArg("guild-id").Short('g').Required(true).Help("Guild GUILD_ID"),
+		glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID"),
+		glap.NewArg("role-id").Short('r').Required(true).Help("Role to give project members ROLE_ID"),
+		glap.NewArg("schedule-pattern").
+			Default("0 * * * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("How often to sync roles, as a cron pattern PATTERN"),
+	}
+
+	return mod.ConfigCommandOptions{
+		Args:       args,
+		KeyArgs:    []string{"guild-id"},
+		ModuleName: moduleName,
+		GetKey: func(m *glap.Matches) string {
+			v, _ := m.GetString("guild-id")
+			return v
+		},
+		GetData: func(m *glap.Matches) any {
+			guildID, _ := m.GetString("guild-id")
+			projectID, _ := m.GetInt64("project-id")
+			roleID, _ := m.GetString("role-id")
+			cronPattern, _ := m.GetString("schedule-pattern")
+
+			return GuildConfig{
+				ID:          guildID,
+				ProjectID:   projectID,
+				RoleID:      roleID,
+				CronPattern: cronPattern,
+			}
+		},
+	}
+}
@@ -0,0 +1,426 @@
+package projectrole
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName      = "projectrole"
+	apiRequestDelay = time.Second
+	membersPageSize = 200
+	// membersMaxPages caps how many members are fetched per sync
+	membersMaxPages = 50
+	maxReportLength = 1900
+	// guild members are listed in pages of up to 1000
+	guildMembersPageSize = 1000
+)
+
+// Module keeps a guild role in sync with membership of an iNaturalist
+// project. Members who have linked an iNaturalist account that is in the
+// project get the role, and everyone else who has it loses it. Listing the
+// guild's members needs the server members intent.
+type Module struct {
+	api                     inat.Api
+	db                      *store.Queries
+	scheduler               *mod.Scheduler
+	logger                  *slog.Logger
+	config                  []GuildConfig
+	slashCommandsRegistered bool
+	configLock              sync.RWMutex
+	syncLock                sync.Mutex
+}
+
+// roleChange is a role that should be given to, or taken from, a member
+type roleChange struct {
+	UserID string
+	Login  string
+	Add    bool
+}
+
+func New(db *store.Queries, scheduler *mod.Scheduler, logger *slog.Logger) (*Module, error) {
+	return &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		logger:    logger,
+	}, nil
+}
+
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Config() []GuildConfig {
+	m.configLock.RLock()
+	defer m.configLock.RUnlock()
+	return m.config
+}
+
+func (m *Module) SetConfig(config []GuildConfig) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	m.config = config
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	config, err := mod.FetchModuleConfiguration[GuildConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+
+	if !m.slashCommandsRegistered {
+		m.registerHandlers(discord)
+	}
+
+	m.logger.Info("started module")
+	m.logger.Info(" -> config", "guilds", m.Config())
+	m.scheduleJobs(ctx, discord)
+	return nil
+}
+
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	config, err := mod.FetchModuleConfiguration[GuildConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.scheduleJobs(ctx, discord)
+	m.logger.Info(" -> config", "guilds", m.Config())
+	return nil
+}
+
+func (m *Module) scheduleJobs(ctx context.Context, discord *discordgo.Session) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		err := m.scheduler.Register(ctx, mod.Job{
+			Name:     mod.JobName(moduleName, "sync", o.ID),
+			Module:   moduleName,
+			Schedule: o.CronPattern,
+			CatchUp:  mod.CatchUpOnce,
+			Run: func(ctx context.Context) error {
+				_, err := m.Sync(ctx, discord, o.ID, true)
+				return err
+			},
+		})
+
+		if err != nil {
+			m.logger.Error("error scheduling role sync", "guild", o.ID, "err", err)
+		}
+	}
+}
+
+func (m *Module) guildConfig(guildID string) (GuildConfig, error) {
+	for _, o := range m.Config() {
+		if o.ID == guildID {
+			return o, nil
+		}
+	}
+
+	return GuildConfig{}, errors.New("guild config not found")
+}
+
+func (m *Module) registerHandlers(discord *discordgo.Session) {
+	m.slashCommandsRegistered = true
+	var adminPermissions int64 = discordgo.PermissionManageRoles
+	command := discordgo.ApplicationCommand{
+		Name:                     "projectrole",
+		Description:              "Check which members should have the iNaturalist project role",
+		DefaultMemberPermissions: &adminPermissions,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "apply",
+				Description: "Update the roles now, instead of only reporting what would change",
+			},
+		},
+	}
+
+	_, err := discord.ApplicationCommandCreate(discord.State.Application.ID, "", &command)
+
+	if err != nil {
+		m.logger.Warn("error creating /projectrole command", "err", err)
+	}
+
+	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type != discordgo.InteractionApplicationCommand ||
+			i.ApplicationCommandData().Name != "projectrole" {
+			return
+		}
+
+		m.handleProjectRole(d, i)
+	})
+
+	m.logger.Info(" -> projectrole slash commands registered")
+}
+
+func (m *Module) handleProjectRole(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	apply := false
+
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == "apply" {
+			apply = option.BoolValue()
+		}
+	}
+
+	if _, err := m.guildConfig(i.GuildID); err != nil {
+		mod.RespondEphemeral(d, i, "The project role hasn't been set up for this server.")
+		return
+	}
+
+	// fetching the project's members takes longer than discord waits for a
+	// response
+	err := d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to /projectrole", "err", err)
+		return
+	}
+
+	go func() {
+		changes, err := m.Sync(context.Background(), d, i.GuildID, apply)
+		content := report(changes, apply)
+
+		if err != nil {
+			m.logger.Error("error syncing project roles", "guild", i.GuildID, "err", err)
+			content = fmt.Sprintf("Sorry, something went wrong: %s", err)
+		}
+
+		_, err = d.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:         &content,
+			AllowedMentions: &discordgo.MessageAllowedMentions{},
+		})
+
+		if err != nil {
+			m.logger.Error("error responding to /projectrole", "err", err)
+		}
+	}()
+}
+
+// Sync works out which members should gain or lose the project role, and
+// updates their roles if `apply` is set
+func (m *Module) Sync(
+	ctx context.Context,
+	discord *discordgo.Session,
+	guildID string,
+	apply bool,
+) ([]roleChange, error) {
+	m.syncLock.Lock()
+	defer m.syncLock.Unlock()
+
+	options, err := m.guildConfig(guildID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := m.fetchProjectMembers(options.ProjectID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := m.db.ListInatAccounts(ctx)
+
+	if err != nil {
+		return nil, fmt.Errorf("error fetching linked accounts: %w", err)
+	}
+
+	linked := make(map[string]store.InatAccount, len(accounts))
+
+	for _, account := range accounts {
+		linked[account.DiscordUserID] = account
+	}
+
+	guildMembers, err := fetchGuildMembers(discord, guildID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changes []roleChange
+		errs    []error
+	)
+
+	for _, member := range guildMembers {
+		if member.User == nil || member.User.Bot {
+			continue
+		}
+
+		account, isLinked := linked[member.User.ID]
+		_, isProjectMember := members[account.InatUserID]
+		shouldHaveRole := isLinked && isProjectMember
+		hasRole := slices.Contains(member.Roles, options.RoleID)
+
+		if shouldHaveRole == hasRole {
+			continue
+		}
+
+		changes = append(changes, roleChange{
+			UserID: member.User.ID,
+			Login:  account.InatLogin,
+			Add:    shouldHaveRole,
+		})
+	}
+
+	if !apply {
+		return changes, errors.Join(errs...)
+	}
+
+	for _, c := range changes {
+		if c.Add {
+			err = discord.GuildMemberRoleAdd(guildID, c.UserID, options.RoleID)
+		} else {
+			err = discord.GuildMemberRoleRemove(guildID, c.UserID, options.RoleID)
+		}
+
+		if err != nil {
+			errs = append(errs, fmt.Errorf("member %s: %w", c.UserID, err))
+			continue
+		}
+
+		m.logger.Info("updated project role", "guild", guildID, "user", c.UserID, "login", c.Login, "add", c.Add)
+	}
+
+	return changes, errors.Join(errs...)
+}
+
+// fetchProjectMembers returns the ids of everyone in a project. It fails
+// rather than return part of the list.
+func (m *Module) fetchProjectMembers(projectID int64) (map[int64]struct{}, error) {
+	members := make(map[int64]struct{})
+
+	for page := 1; page <= membersMaxPages; page++ {
+		r, err := m.api.FetchProjectMembers(projectID, url.Values{
+			"page":     {strconv.Itoa(page)},
+			"per_page": {strconv.Itoa(membersPageSize)},
+		})
+
+		if err != nil {
+			return nil, fmt.Errorf("error fetching project members: %w", err)
+		}
+
+		for _, member := range r.Results {
+			members[member.User.ID] = struct{}{}
+		}
+
+		if len(r.Results) < membersPageSize {
+			return members, nil
+		}
+
+		time.Sleep(apiRequestDelay)
+	}
+
+	// roles are taken from anyone missing from the list, so a partial one
+	// can't be used
+	return nil, fmt.Errorf(
+		"error fetching project members: more than %d members",
+		membersMaxPages*membersPageSize,
+	)
+}
+
+// fetchGuildMembers returns everyone in a guild
+func fetchGuildMembers(discord *discordgo.Session, guildID string) ([]*discordgo.Member, error) {
+	var (
+		members []*discordgo.Member
+		after   string
+	)
+
+	for {
+		page, err := discord.GuildMembers(guildID, after, guildMembersPageSize)
+
+		if err != nil {
+			return nil, fmt.Errorf("error fetching guild members: %w", err)
+		}
+
+		members = append(members, page...)
+
+		if len(page) < guildMembersPageSize {
+			return members, nil
+		}
+
+		after = page[len(page)-1].User.ID
+	}
+}
+
+// report describes the changes a sync made, or would make
+func report(changes []roleChange, applied bool) string {
+	if len(changes) <= 0 {
+		return "Everyone's project role is up to date."
+	}
+
+	add, remove := "Would give the role to", "Would take the role from"
+
+	if applied {
+		add, remove = "Gave the role to", "Took the role from"
+	}
+
+	var b strings.Builder
+
+	for i, c := range changes {
+		verb := remove
+
+		if c.Add {
+			verb = add
+		}
+
+		login := c.Login
+
+		if login == "" {
+			login = "not linked"
+		}
+
+		line := fmt.Sprintf("%s <@%s> (%s)\n", verb, c.UserID, login)
+
+		if b.Len()+len(line) > maxReportLength {
+			fmt.Fprintf(&b, "…and %d more", len(changes)-i)
+			break
+		}
+
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
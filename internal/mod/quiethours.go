@@ -0,0 +1,76 @@
+package mod
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseQuietHours parses a range like "22:00-07:00" into minutes after
+// midnight
+func ParseQuietHours(v string) (int, int, error) {
+	start, end, ok := strings.Cut(v, "-")
+
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid quiet hours '%s', expected HH:MM-HH:MM", v)
+	}
+
+	parse := func(s string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+
+		if err != nil {
+			return 0, fmt.Errorf("invalid quiet hours '%s', expected HH:MM-HH:MM", v)
+		}
+
+		return t.Hour()*60 + t.Minute(), nil
+	}
+
+	startMinute, err := parse(start)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	endMinute, err := parse(end)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return startMinute, endMinute, nil
+}
+
+// ValidateQuietHours checks a quiet hours range, for use as an arg validator
+func ValidateQuietHours(v string) error {
+	_, _, err := ParseQuietHours(v)
+	return err
+}
+
+// InQuietHours reports whether `now` falls within the quiet hours, in the
+// given timezone. Ranges that end before they start wrap past midnight.
+func InQuietHours(quietHours, timezone string, now time.Time) (bool, error) {
+	if quietHours == "" {
+		return false, nil
+	}
+
+	start, end, err := ParseQuietHours(quietHours)
+
+	if err != nil {
+		return false, err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+
+	if err != nil {
+		return false, fmt.Errorf("invalid timezone '%s': %w", timezone, err)
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+
+	if start <= end {
+		return minute >= start && minute < end, nil
+	}
+
+	return minute >= start || minute < end, nil
+}
@@ -0,0 +1,349 @@
+package mod
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/fx"
+
+	"github.com/synic/buggins/internal/store"
+)
+
+type CatchUpPolicy string
+
+const (
+	// CatchUpSkip drops any runs that were missed while the bot was down
+	CatchUpSkip CatchUpPolicy = "skip"
+	// CatchUpOnce runs the job once at startup if any runs were missed
+	CatchUpOnce CatchUpPolicy = "once"
+	// CatchUpAll runs the job once for every run that was missed, up to
+	// `maxCatchUpRuns`
+	CatchUpAll CatchUpPolicy = "all"
+)
+
+var (
+	CatchUpPolicies = []string{string(CatchUpSkip), string(CatchUpOnce), string(CatchUpAll)}
+	maxCatchUpRuns  = 10
+	jobRunsToKeep   = 50
+	cronParser      = cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	)
+)
+
+type Job struct {
+	Run      func(ctx context.Context) error
+	Name     string
+	Module   string
+	Schedule string
+	Timezone string
+	CatchUp  CatchUpPolicy
+}
+
+type UpcomingRun struct {
+	NextRunAt time.Time
+	Job       store.ScheduledJob
+}
+
+type scheduledJob struct {
+	job      Job
+	schedule cron.Schedule
+	running  atomic.Bool
+	entryID  cron.EntryID
+}
+
+// Scheduler runs jobs that modules register on a cron schedule. The last run
+// of each job is stored, so runs that were missed while the bot was down can
+// be caught up on according to the job's `CatchUpPolicy`.
+type Scheduler struct {
+	db       *store.Queries
+	logger   *slog.Logger
+	cron     *cron.Cron
+	jobs     map[string]*scheduledJob
+	jobsLock sync.RWMutex
+	// ctx is passed to every run, and is cancelled when the scheduler stops
+	ctx      context.Context
+	cancel   context.CancelFunc
+	catchUps sync.WaitGroup
+}
+
+func NewScheduler(db *store.Queries, logger *slog.Logger) (*Scheduler, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Scheduler{
+		db:     db,
+		logger: logger,
+		cron:   cron.New(cron.WithParser(cronParser), cron.WithLocation(time.UTC)),
+		jobs:   make(map[string]*scheduledJob),
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+func SchedulerProvider(
+	lc fx.Lifecycle,
+	db *store.Queries,
+	logger *slog.Logger,
+) (*Scheduler, error) {
+	s, err := NewScheduler(db, logger.With("mod", "scheduler"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			s.cron.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			s.logger.Info("stopping scheduler...")
+			s.Stop()
+			return nil
+		},
+	})
+
+	return s, nil
+}
+
+func ParseCatchUpPolicy(v string) (CatchUpPolicy, error) {
+	if v == "" {
+		return CatchUpOnce, nil
+	}
+
+	if !slices.Contains(CatchUpPolicies, v) {
+		return "", fmt.Errorf("unknown catch up policy '%s'", v)
+	}
+
+	return CatchUpPolicy(v), nil
+}
+
+// ValidateSchedule checks that a cron pattern and timezone can be scheduled
+func ValidateSchedule(pattern, timezone string) error {
+	_, err := parseSchedule(pattern, timezone)
+	return err
+}
+
+// parseSchedule parses a cron pattern in a timezone, which defaults to UTC
+func parseSchedule(pattern, timezone string) (cron.Schedule, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("invalid timezone '%s': %w", timezone, err)
+	}
+
+	schedule, err := cronParser.Parse(fmt.Sprintf("CRON_TZ=%s %s", timezone, pattern))
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule '%s': %w", pattern, err)
+	}
+
+	return schedule, nil
+}
+
+// Register schedules a job, replacing any job that has the same name. If runs
+// of the job were missed since it last ran, they are caught up on right away.
+func (s *Scheduler) Register(ctx context.Context, job Job) error {
+	if job.CatchUp == "" {
+		job.CatchUp = CatchUpOnce
+	}
+
+	schedule, err := parseSchedule(job.Schedule, job.Timezone)
+
+	if err != nil {
+		return err
+	}
+
+	row, err := s.db.SaveScheduledJob(ctx, store.SaveScheduledJobParams{
+		Name:     job.Name,
+		Module:   job.Module,
+		Schedule: job.Schedule,
+		Timezone: job.Timezone,
+		CatchUp:  string(job.CatchUp),
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving job %s: %w", job.Name, err)
+	}
+
+	sj := &scheduledJob{job: job, schedule: schedule}
+	sj.entryID = s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.run(sj, false)
+	}))
+
+	s.jobsLock.Lock()
+	if existing, ok := s.jobs[job.Name]; ok {
+		s.cron.Remove(existing.entryID)
+	}
+	s.jobs[job.Name] = sj
+	s.jobsLock.Unlock()
+
+	// jobs that have never run are measured from when they were first
+	// registered, so a post that was due before the first run isn't lost
+	since := row.CreatedAt
+
+	if row.LastRunAt.Valid {
+		since = row.LastRunAt.Time
+	}
+
+	missed := missedRuns(schedule, since, time.Now())
+
+	if missed > 0 && job.CatchUp != CatchUpSkip {
+		runs := 1
+
+		if job.CatchUp == CatchUpAll {
+			runs = missed
+		}
+
+		s.logger.Info("catching up on missed runs", "job", job.Name, "missed", missed, "runs", runs)
+
+		s.catchUps.Go(func() {
+			for range runs {
+				if s.ctx.Err() != nil {
+					return
+				}
+
+				s.run(sj, true)
+			}
+		})
+	}
+
+	return nil
+}
+
+// Stop cancels the context of running jobs, stops catching up on missed
+// runs and waits for running jobs to finish
+func (s *Scheduler) Stop() {
+	s.cancel()
+	<-s.cron.Stop().Done()
+	s.catchUps.Wait()
+}
+
+// RemoveModuleJobs unschedules every job registered by a module
+func (s *Scheduler) RemoveModuleJobs(module string) {
+	s.jobsLock.Lock()
+	defer s.jobsLock.Unlock()
+
+	for name, sj := range s.jobs {
+		if sj.job.Module == module {
+			s.cron.Remove(sj.entryID)
+			delete(s.jobs, name)
+		}
+	}
+}
+
+// Upcoming returns the next run of every scheduled job, soonest first
+func (s *Scheduler) Upcoming(ctx context.Context) ([]UpcomingRun, error) {
+	rows, err := s.db.FindScheduledJobs(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.jobsLock.RLock()
+	defer s.jobsLock.RUnlock()
+
+	upcoming := make([]UpcomingRun, 0, len(s.jobs))
+
+	for _, row := range rows {
+		sj, ok := s.jobs[row.Name]
+
+		if !ok {
+			continue
+		}
+
+		upcoming = append(upcoming, UpcomingRun{
+			NextRunAt: sj.schedule.Next(time.Now()),
+			Job:       row,
+		})
+	}
+
+	slices.SortFunc(upcoming, func(a, b UpcomingRun) int {
+		return a.NextRunAt.Compare(b.NextRunAt)
+	})
+
+	return upcoming, nil
+}
+
+func (s *Scheduler) run(sj *scheduledJob, catchUp bool) {
+	name := sj.job.Name
+
+	if !sj.running.CompareAndSwap(false, true) {
+		s.logger.Warn("job is still running, skipping", "job", name)
+		return
+	}
+
+	defer sj.running.Store(false)
+
+	startedAt := time.Now().UTC()
+	err := sj.job.Run(s.ctx)
+	finishedAt := time.Now().UTC()
+
+	// the run is recorded even when it was cut short by the scheduler
+	// stopping
+	ctx := context.WithoutCancel(s.ctx)
+
+	status, errText := "success", ""
+
+	if err != nil {
+		status, errText = "error", err.Error()
+		s.logger.Error("job failed", "job", name, "err", err)
+	}
+
+	err = s.db.UpdateScheduledJobLastRun(ctx, store.UpdateScheduledJobLastRunParams{
+		Name:       name,
+		LastRunAt:  sql.NullTime{Time: startedAt, Valid: true},
+		LastStatus: status,
+		LastError:  errText,
+	})
+
+	if err != nil {
+		s.logger.Error("error saving job run", "job", name, "err", err)
+	}
+
+	err = s.db.CreateJobRun(ctx, store.CreateJobRunParams{
+		JobName:    name,
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Status:     status,
+		Error:      errText,
+		CatchUp:    catchUp,
+	})
+
+	if err == nil {
+		err = s.db.DeleteOldJobRuns(ctx, store.DeleteOldJobRunsParams{
+			JobName: name,
+			Keep:    int64(jobRunsToKeep),
+		})
+	}
+
+	if err != nil {
+		s.logger.Error("error saving job run history", "job", name, "err", err)
+	}
+}
+
+func missedRuns(schedule cron.Schedule, since, now time.Time) int {
+	missed := 0
+
+	for t := schedule.Next(since); !t.After(now) && missed < maxCatchUpRuns; t = schedule.Next(t) {
+		missed += 1
+	}
+
+	return missed
+}
+
+// JobName builds a job name from its parts, for example
+// `JobName("inatobs", "post", channelID)`
+func JobName(parts ...string) string {
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,74 @@
+package mod
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+)
+
+var (
+	// MaxAutocompleteChoices is the most choices discord will show
+	MaxAutocompleteChoices = 25
+	// discord limits choice names to 100 characters
+	maxChoiceNameLength = 100
+)
+
+// TaxonChoices searches for taxa to autocomplete a slash command option. The
+// choice values are taxon ids, see `ResolveTaxon`.
+func TaxonChoices(api inat.Api, q string) ([]*discordgo.ApplicationCommandOptionChoice, error) {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, MaxAutocompleteChoices)
+	q = strings.TrimSpace(q)
+
+	if len(q) < 2 {
+		return choices, nil
+	}
+
+	r, err := api.AutocompleteTaxa(q)
+
+	if err != nil {
+		return choices, err
+	}
+
+	for _, taxon := range r.Results {
+		if len(choices) >= MaxAutocompleteChoices {
+			break
+		}
+
+		name := taxon.DisplayName()
+
+		if r := []rune(name); len(r) > maxChoiceNameLength {
+			name = string(r[:maxChoiceNameLength])
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  name,
+			Value: strconv.FormatInt(taxon.ID, 10),
+		})
+	}
+
+	return choices, nil
+}
+
+// ResolveTaxon finds a taxon. Autocompleted values are ids, anything else is
+// searched for by name.
+func ResolveTaxon(api inat.Api, taxon string) (inat.Taxon, error) {
+	if id, err := strconv.ParseInt(taxon, 10, 64); err == nil {
+		return api.FetchTaxon(id)
+	}
+
+	r, err := api.Search([]string{"taxa"}, taxon)
+
+	if err != nil {
+		return inat.Taxon{}, err
+	}
+
+	if len(r.Results) <= 0 {
+		return inat.Taxon{}, errors.New("no matching taxa")
+	}
+
+	return r.Results[0].Record.Taxon(), nil
+}
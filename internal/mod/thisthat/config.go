@@ -17,7 +17,7 @@ func ConfigCommandOptions() mod.ConfigCommandOptions {
 
 	return mod.ConfigCommandOptions{
 		Args:       args,
-		KeyArg:     "channel-id",
+		KeyArgs:    []string{"channel-id"},
 		ModuleName: moduleName,
 		GetKey: func(m *glap.Matches) string {
 			v, _ := m.GetString("channel-id")
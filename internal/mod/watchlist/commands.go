@@ -0,0 +1,346 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+func (m *Module) registerHandlers(discord *discordgo.Session) {
+	m.slashCommandsRegistered = true
+	dmPermission := false
+	commands := []*discordgo.ApplicationCommand{
+		{
+			Name:         "watch",
+			Description:  "Get a dm when someone in the project observes a taxon",
+			DMPermission: &dmPermission,
+			Options: []*discordgo.ApplicationCommandOption{{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "taxon",
+				Description:  "Taxon to watch, including everything below it",
+				Required:     true,
+				Autocomplete: true,
+			}},
+		},
+		{
+			Name:         "unwatch",
+			Description:  "Stop watching a taxon",
+			DMPermission: &dmPermission,
+			Options: []*discordgo.ApplicationCommandOption{{
+				Type:         discordgo.ApplicationCommandOptionString,
+				Name:         "taxon",
+				Description:  "Taxon to stop watching",
+				Required:     true,
+				Autocomplete: true,
+			}},
+		},
+		{
+			Name:         "watchlist",
+			Description:  "Show the taxa you're watching, and change when you get dms",
+			DMPermission: &dmPermission,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "quiet_hours",
+					Description: "Don't send dms between these times, like 22:00-07:00, or \"off\"",
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timezone",
+					Description: "Timezone for your quiet hours, like America/Denver",
+				},
+			},
+		},
+	}
+
+	for _, command := range commands {
+		_, err := discord.ApplicationCommandCreate(discord.State.Application.ID, "", command)
+
+		if err != nil {
+			m.logger.Warn("error creating command", "command", command.Name, "err", err)
+		}
+	}
+
+	discord.AddHandler(func(d *discordgo.Session, i *discordgo.InteractionCreate) {
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			switch i.ApplicationCommandData().Name {
+			case "watch":
+				m.handleWatch(d, i)
+			case "unwatch":
+				m.handleUnwatch(d, i)
+			case "watchlist":
+				m.handleWatchlist(d, i)
+			}
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			switch i.ApplicationCommandData().Name {
+			case "watch":
+				m.handleWatchAutocomplete(d, i)
+			case "unwatch":
+				m.handleUnwatchAutocomplete(d, i)
+			}
+		}
+	})
+
+	m.logger.Info(" -> watchlist slash commands registered")
+}
+
+func (m *Module) handleWatch(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	options, err := m.guildConfig(i.GuildID)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "The watchlist hasn't been set up for this server.")
+		return
+	}
+
+	// looking up the taxon can take longer than discord waits for a response
+	err = d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+
+	if err != nil {
+		m.logger.Error("error responding to /watch", "err", err)
+		return
+	}
+
+	go func() {
+		content := m.watch(options, i.GuildID, mod.InteractionUser(i).ID, optionValue(i, "taxon"))
+		_, err := d.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content})
+
+		if err != nil {
+			m.logger.Error("error responding to /watch", "err", err)
+		}
+	}()
+}
+
+// watch adds a taxon to a member's watchlist for `/watch`, and returns the
+// response to show them
+func (m *Module) watch(options GuildConfig, guildID, userID, query string) string {
+	ctx := context.Background()
+	watches, err := m.db.ListUserTaxonWatches(ctx, store.ListUserTaxonWatchesParams{
+		GuildID:       guildID,
+		DiscordUserID: userID,
+	})
+
+	if err != nil {
+		m.logger.Error("error fetching watches", "user", userID, "err", err)
+		return "Sorry, something went wrong, please try again later."
+	}
+
+	if int64(len(watches)) >= options.maxWatches() {
+		return fmt.Sprintf("You're already watching %d taxa, `/unwatch` one to make room.", len(watches))
+	}
+
+	taxon, err := mod.ResolveTaxon(m.api, query)
+
+	if err != nil {
+		return "Sorry, I couldn't find that taxon."
+	}
+
+	rows, err := m.db.CreateTaxonWatch(ctx, store.CreateTaxonWatchParams{
+		GuildID:       guildID,
+		DiscordUserID: userID,
+		TaxonID:       taxon.ID,
+		TaxonName:     taxon.DisplayName(),
+	})
+
+	if err != nil {
+		m.logger.Error("error saving watch", "user", userID, "taxon", taxon.ID, "err", err)
+		return "Sorry, something went wrong, please try again later."
+	}
+
+	if rows <= 0 {
+		return fmt.Sprintf("You're already watching %s.", taxon.DisplayName())
+	}
+
+	m.logger.Info("watching taxon", "user", userID, "taxon", taxon.ID)
+	return fmt.Sprintf(
+		"You'll get a dm when someone in the project observes %s. Make sure you allow dms from this server!",
+		taxon.DisplayName(),
+	)
+}
+
+func (m *Module) handleUnwatch(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := mod.InteractionUser(i).ID
+	taxonID, err := strconv.ParseInt(optionValue(i, "taxon"), 10, 64)
+
+	if err != nil {
+		mod.RespondEphemeral(d, i, "Pick one of the taxa you're watching from the list.")
+		return
+	}
+
+	rows, err := m.db.DeleteTaxonWatch(context.Background(), store.DeleteTaxonWatchParams{
+		GuildID:       i.GuildID,
+		DiscordUserID: userID,
+		TaxonID:       taxonID,
+	})
+
+	if err != nil {
+		m.logger.Error("error deleting watch", "user", userID, "taxon", taxonID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	if rows <= 0 {
+		mod.RespondEphemeral(d, i, "You weren't watching that taxon.")
+		return
+	}
+
+	mod.RespondEphemeral(d, i, "You've stopped watching that taxon.")
+}
+
+// handleWatchlist lists a member's watches, after updating their quiet hours
+// if they were given
+func (m *Module) handleWatchlist(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	ctx := context.Background()
+	userID := mod.InteractionUser(i).ID
+	settings, err := m.db.FindWatchSettings(ctx, userID)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		m.logger.Error("error fetching watch settings", "user", userID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	quietHours, timezone := settings.QuietHours, settings.Timezone
+	changed := false
+
+	if v := optionValue(i, "quiet_hours"); v != "" {
+		quietHours, changed = v, true
+
+		if strings.EqualFold(v, "off") {
+			quietHours = ""
+		} else if err := mod.ValidateQuietHours(v); err != nil {
+			mod.RespondEphemeral(d, i, "Quiet hours should look like `22:00-07:00`.")
+			return
+		}
+	}
+
+	if v := optionValue(i, "timezone"); v != "" {
+		if _, err := time.LoadLocation(v); err != nil {
+			mod.RespondEphemeral(d, i, fmt.Sprintf("I don't know the timezone `%s`, try one like `America/Denver`.", v))
+			return
+		}
+
+		timezone, changed = v, true
+	}
+
+	if changed {
+		err = m.db.SaveWatchSettings(ctx, store.SaveWatchSettingsParams{
+			DiscordUserID: userID,
+			QuietHours:    quietHours,
+			Timezone:      timezone,
+		})
+
+		if err != nil {
+			m.logger.Error("error saving watch settings", "user", userID, "err", err)
+			mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+			return
+		}
+	}
+
+	watches, err := m.db.ListUserTaxonWatches(ctx, store.ListUserTaxonWatchesParams{
+		GuildID:       i.GuildID,
+		DiscordUserID: userID,
+	})
+
+	if err != nil {
+		m.logger.Error("error fetching watches", "user", userID, "err", err)
+		mod.RespondEphemeral(d, i, "Sorry, something went wrong, please try again later.")
+		return
+	}
+
+	var b strings.Builder
+
+	if len(watches) <= 0 {
+		b.WriteString("You aren't watching anything yet, add a taxon with `/watch`.\n")
+	} else {
+		b.WriteString("You're watching:\n")
+
+		for _, w := range watches {
+			fmt.Fprintf(&b, "- [%s](https://inaturalist.org/taxa/%d)\n", w.TaxonName, w.TaxonID)
+		}
+	}
+
+	if quietHours != "" {
+		if timezone == "" {
+			timezone = "UTC"
+		}
+
+		fmt.Fprintf(&b, "\nQuiet hours: %s (%s)", quietHours, timezone)
+	}
+
+	mod.RespondEphemeral(d, i, b.String())
+}
+
+func (m *Module) handleWatchAutocomplete(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	q := optionValue(i, "taxon")
+	choices, err := mod.TaxonChoices(m.api, q)
+
+	if err != nil {
+		m.logger.Error("error searching taxa", "q", q, "err", err)
+	}
+
+	respondChoices(d, i, choices)
+}
+
+// handleUnwatchAutocomplete suggests the taxa the member is watching
+func (m *Module) handleUnwatchAutocomplete(d *discordgo.Session, i *discordgo.InteractionCreate) {
+	q := strings.ToLower(optionValue(i, "taxon"))
+	userID := mod.InteractionUser(i).ID
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	watches, err := m.db.ListUserTaxonWatches(context.Background(), store.ListUserTaxonWatchesParams{
+		GuildID:       i.GuildID,
+		DiscordUserID: userID,
+	})
+
+	if err != nil {
+		m.logger.Error("error fetching watches", "user", userID, "err", err)
+	}
+
+	for _, w := range watches {
+		if len(choices) >= mod.MaxAutocompleteChoices {
+			break
+		}
+
+		if strings.Contains(strings.ToLower(w.TaxonName), q) {
+			choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+				Name:  w.TaxonName,
+				Value: strconv.FormatInt(w.TaxonID, 10),
+			})
+		}
+	}
+
+	respondChoices(d, i, choices)
+}
+
+func optionValue(i *discordgo.InteractionCreate, name string) string {
+	for _, option := range i.ApplicationCommandData().Options {
+		if option.Name == name {
+			return strings.TrimSpace(option.StringValue())
+		}
+	}
+
+	return ""
+}
+
+func respondChoices(
+	d *discordgo.Session,
+	i *discordgo.InteractionCreate,
+	choices []*discordgo.ApplicationCommandOptionChoice,
+) {
+	d.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{Choices: choices},
+	})
+}
@@ -0,0 +1,92 @@
+package watchlist
+
+import (
+	"time"
+
+	"github.com/synic/glap"
+
+	"github.com/synic/buggins/internal/mod"
+)
+
+var (
+	defaultDMInterval = time.Hour
+	defaultMaxWatches = int64(25)
+)
+
+// GuildConfig lets members of a guild watch for observations of taxa in an
+// iNaturalist project
+type GuildConfig struct {
+	ID          string `json:"id"`
+	ProjectID   int64  `json:"inat_project_id"`
+	CronPattern string `json:"cron_pattern"`
+	// DMInterval is the least amount of time between two dms to a member, new
+	// sightings are batched up until the next one
+	DMInterval string `json:"dm_interval"`
+	MaxWatches int64  `json:"max_watches"`
+}
+
+func (c GuildConfig) dmInterval() time.Duration {
+	d, err := time.ParseDuration(c.DMInterval)
+
+	if err != nil || d < 0 {
+		return defaultDMInterval
+	}
+
+	return d
+}
+
+func (c GuildConfig) maxWatches() int64 {
+	if c.MaxWatches <= 0 {
+		return defaultMaxWatches
+	}
+
+	return c.MaxWatches
+}
+
+func validateDuration(v string) error {
+	_, err := time.ParseDuration(v)
+	return err
+}
+
+func ConfigCommandOptions() mod.ConfigCommandOptions {
+	args := []*glap.Arg{
+		glap.NewArg("guild-id").Short('g').Required(true).Help("Guild GUILD_ID"),
+		glap.NewArg("project-id").Short('p').Required(true).Help("Project PROJECT_ID"),
+		glap.NewArg("schedule-pattern").
+			Default("*/10 * * * *").
+			Validator(func(v string) error { return mod.ValidateSchedule(v, "") }).
+			Help("How often to check for new observations, as a cron pattern PATTERN"),
+		glap.NewArg("dm-interval").
+			Default("1h").
+			Validator(validateDuration).
+			Help("Send each member at most one dm every DURATION"),
+		glap.NewArg("max-watches").
+			Default("25").
+			Help("How many taxa each member can watch COUNT"),
+	}
+
+	return mod.ConfigCommandOptions{
+		Args:       args,
+		KeyArgs:    []string{"guild-id"},
+		ModuleName: moduleName,
+		GetKey: func(m *glap.Matches) string {
+			v, _ := m.GetString("guild-id")
+			return v
+		},
+		GetData: func(m *glap.Matches) any {
+			guildID, _ := m.GetString("guild-id")
+			projectID, _ := m.GetInt64("project-id")
+			cronPattern, _ := m.GetString("schedule-pattern")
+			dmInterval, _ := m.GetString("dm-interval")
+			maxWatches, _ := m.GetInt64("max-watches")
+
+			return GuildConfig{
+				ID:          guildID,
+				ProjectID:   projectID,
+				CronPattern: cronPattern,
+				DMInterval:  dmInterval,
+				MaxWatches:  maxWatches,
+			}
+		},
+	}
+}
@@ -0,0 +1,522 @@
+package watchlist
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/synic/buggins/internal/inat"
+	"github.com/synic/buggins/internal/mod"
+	"github.com/synic/buggins/internal/store"
+)
+
+var (
+	moduleName      = "watchlist"
+	embedColor      = 5763719
+	apiRequestDelay = time.Second
+	pageSize        = 200
+	// maxPages caps how many new observations are checked per poll, the rest
+	// are picked up by the next one
+	maxPages   = 5
+	maxDMItems = 15
+)
+
+// Module lets members watch for observations of taxa they care about. New
+// project observations are matched against the watched taxa and their
+// descendants, and matches are sent to the member in batched dms.
+type Module struct {
+	api                     inat.Api
+	db                      *store.Queries
+	scheduler               *mod.Scheduler
+	outbox                  *mod.Outbox
+	logger                  *slog.Logger
+	config                  []GuildConfig
+	slashCommandsRegistered bool
+	configLock              sync.RWMutex
+	pollLock                sync.Mutex
+}
+
+// sighting is a watched observation waiting to be sent to a member
+type sighting struct {
+	Taxon       string `json:"taxon"`
+	Observer    string `json:"observer"`
+	Watch       string `json:"watch"`
+	PhotoURL    string `json:"photo_url"`
+	Attribution string `json:"attribution"`
+	ID          int64  `json:"id"`
+}
+
+func New(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (*Module, error) {
+	return &Module{
+		api:       inat.New(),
+		db:        db,
+		scheduler: scheduler,
+		outbox:    outbox,
+		logger:    logger,
+	}, nil
+}
+
+func Provider(
+	db *store.Queries,
+	scheduler *mod.Scheduler,
+	outbox *mod.Outbox,
+	logger *slog.Logger,
+) (mod.ModuleProviderResult, error) {
+	module, err := New(db, scheduler, outbox, logger.With("mod", moduleName))
+
+	if err != nil {
+		return mod.ModuleProviderResult{}, err
+	}
+
+	return mod.ModuleProviderResult{Module: module}, nil
+}
+
+func (m *Module) Name() string {
+	return moduleName
+}
+
+func (m *Module) Config() []GuildConfig {
+	m.configLock.RLock()
+	defer m.configLock.RUnlock()
+	return m.config
+}
+
+func (m *Module) SetConfig(config []GuildConfig) {
+	m.configLock.Lock()
+	defer m.configLock.Unlock()
+	m.config = config
+}
+
+func (m *Module) Start(ctx context.Context, discord *discordgo.Session, db *store.Queries) error {
+	config, err := mod.FetchModuleConfiguration[GuildConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+
+	if !m.slashCommandsRegistered {
+		m.registerHandlers(discord)
+	}
+
+	m.logger.Info("started module")
+	m.logger.Info(" -> config", "guilds", m.Config())
+	m.scheduleJobs(ctx, discord)
+	return nil
+}
+
+func (m *Module) ReloadConfig(
+	ctx context.Context,
+	discord *discordgo.Session,
+	db *store.Queries,
+) error {
+	config, err := mod.FetchModuleConfiguration[GuildConfig](ctx, db, moduleName)
+
+	if err != nil {
+		return err
+	}
+
+	m.SetConfig(config)
+	m.scheduleJobs(ctx, discord)
+	m.logger.Info(" -> config", "guilds", m.Config())
+	return nil
+}
+
+func (m *Module) scheduleJobs(ctx context.Context, discord *discordgo.Session) {
+	m.scheduler.RemoveModuleJobs(moduleName)
+
+	for _, o := range m.Config() {
+		err := m.scheduler.Register(ctx, mod.Job{
+			Name:     mod.JobName(moduleName, "poll", o.ID),
+			Module:   moduleName,
+			Schedule: o.CronPattern,
+			CatchUp:  mod.CatchUpOnce,
+			Run: func(ctx context.Context) error {
+				return m.Poll(ctx, discord, o.ID)
+			},
+		})
+
+		if err != nil {
+			m.logger.Error("error scheduling watchlist poll", "guild", o.ID, "err", err)
+		}
+	}
+}
+
+func (m *Module) guildConfig(guildID string) (GuildConfig, error) {
+	for _, o := range m.Config() {
+		if o.ID == guildID {
+			return o, nil
+		}
+	}
+
+	return GuildConfig{}, errors.New("guild config not found")
+}
+
+// Poll matches the observations added to the project since the last poll
+// against the guild's watches, then sends the members who are due a dm
+// everything they've been waiting for. The first poll only records where the
+// project is at.
+func (m *Module) Poll(ctx context.Context, discord *discordgo.Session, guildID string) error {
+	m.pollLock.Lock()
+	defer m.pollLock.Unlock()
+
+	options, err := m.guildConfig(guildID)
+
+	if err != nil {
+		return err
+	}
+
+	mark, err := m.db.FindWatchMark(ctx, guildID)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error fetching watch mark: %w", err)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) || mark.ProjectID != options.ProjectID {
+		return m.resetMark(ctx, options)
+	}
+
+	watches, err := m.db.ListGuildTaxonWatches(ctx, guildID)
+
+	if err != nil {
+		return fmt.Errorf("error fetching watches: %w", err)
+	}
+
+	lastID, err := m.matchNewObservations(ctx, options, watches, mark.LastID)
+
+	if err != nil {
+		return err
+	}
+
+	err = m.db.SaveWatchMark(ctx, store.SaveWatchMarkParams{
+		GuildID:   guildID,
+		ProjectID: options.ProjectID,
+		LastID:    lastID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving watch mark: %w", err)
+	}
+
+	return m.deliver(ctx, discord, options)
+}
+
+// matchNewObservations queues a sighting for every watch that matches an
+// observation above `lastID`, and returns the id of the newest observation
+// that was checked
+func (m *Module) matchNewObservations(
+	ctx context.Context,
+	options GuildConfig,
+	watches []store.TaxonWatch,
+	lastID int64,
+) (int64, error) {
+	for page := range maxPages {
+		if page > 0 {
+			time.Sleep(apiRequestDelay)
+		}
+
+		r, err := m.api.FetchObservations(url.Values{
+			"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+			"id_above":   {strconv.FormatInt(lastID, 10)},
+			"order":      {"asc"},
+			"order_by":   {"id"},
+			"per_page":   {strconv.Itoa(pageSize)},
+		})
+
+		if err != nil {
+			return lastID, fmt.Errorf("error fetching new observations: %w", err)
+		}
+
+		for _, o := range r.Results {
+			if err := m.matchObservation(ctx, options, watches, o); err != nil {
+				return lastID, err
+			}
+
+			lastID = o.ID
+		}
+
+		if len(r.Results) < pageSize {
+			break
+		}
+	}
+
+	return lastID, nil
+}
+
+func (m *Module) matchObservation(
+	ctx context.Context,
+	options GuildConfig,
+	watches []store.TaxonWatch,
+	o inat.Observation,
+) error {
+	if o.Taxon.ID == 0 {
+		return nil
+	}
+
+	ancestry := append(slices.Clone(o.Taxon.AncestorIDs), o.Taxon.ID)
+	var observer string
+
+	// members don't need to hear about their own observations
+	if account, err := m.db.FindInatAccountByInatUser(ctx, o.User.ID); err == nil {
+		observer = account.DiscordUserID
+	}
+
+	for _, w := range watches {
+		if w.DiscordUserID == observer || !slices.Contains(ancestry, w.TaxonID) {
+			continue
+		}
+
+		s := sighting{
+			ID:       o.ID,
+			Observer: o.User.DisplayName(),
+			Watch:    w.TaxonName,
+		}
+
+		if o.Taxon.PreferredCommonName != "" {
+			s.Taxon = fmt.Sprintf("%s (%s)", o.Taxon.Name, o.Taxon.PreferredCommonName)
+		} else {
+			s.Taxon = o.Taxon.Name
+		}
+
+		// photos with all rights reserved aren't shown
+		if photos := o.LicensedPhotos(); len(photos) > 0 {
+			s.PhotoURL = photos[0].Medium()
+			s.Attribution = photos[0].Attribution
+		}
+
+		data, err := json.Marshal(s)
+
+		if err != nil {
+			return err
+		}
+
+		// a member watching more than one of the observation's ancestors
+		// still only hears about it once
+		err = m.db.CreateWatchNotification(ctx, store.CreateWatchNotificationParams{
+			GuildID:       options.ID,
+			DiscordUserID: w.DiscordUserID,
+			ObservationID: o.ID,
+			Data:          string(data),
+		})
+
+		if err != nil {
+			return fmt.Errorf("error saving watch notification: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Module) resetMark(ctx context.Context, options GuildConfig) error {
+	r, err := m.api.FetchObservations(url.Values{
+		"project_id": {strconv.FormatInt(options.ProjectID, 10)},
+		"order":      {"desc"},
+		"order_by":   {"id"},
+		"per_page":   {"1"},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error fetching newest observation: %w", err)
+	}
+
+	var lastID int64
+
+	if len(r.Results) > 0 {
+		lastID = r.Results[0].ID
+	}
+
+	m.logger.Info("starting watchlist", "guild", options.ID, "last_id", lastID)
+
+	err = m.db.SaveWatchMark(ctx, store.SaveWatchMarkParams{
+		GuildID:   options.ID,
+		ProjectID: options.ProjectID,
+		LastID:    lastID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error saving watch mark: %w", err)
+	}
+
+	return nil
+}
+
+// deliver sends each member their waiting sightings in a single dm, unless
+// it's their quiet hours or they've had a dm too recently
+func (m *Module) deliver(ctx context.Context, discord *discordgo.Session, options GuildConfig) error {
+	notifications, err := m.db.ListWatchNotifications(ctx, options.ID)
+
+	if err != nil {
+		return fmt.Errorf("error fetching watch notifications: %w", err)
+	}
+
+	var (
+		errs []error
+		now  = time.Now()
+	)
+
+	// notifications are ordered by member, send each member's in one batch
+	for len(notifications) > 0 {
+		userID := notifications[0].DiscordUserID
+		end := 1
+
+		for end < len(notifications) && notifications[end].DiscordUserID == userID {
+			end++
+		}
+
+		batch := notifications[:end]
+		notifications = notifications[end:]
+
+		if err := m.deliverBatch(ctx, discord, options, userID, batch, now); err != nil {
+			m.logger.Error("error sending watchlist dm", "user", userID, "err", err)
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Module) deliverBatch(
+	ctx context.Context,
+	discord *discordgo.Session,
+	options GuildConfig,
+	userID string,
+	notifications []store.WatchNotification,
+	now time.Time,
+) error {
+	settings, err := m.db.FindWatchSettings(ctx, userID)
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("error fetching watch settings: %w", err)
+	}
+
+	if quiet, err := mod.InQuietHours(settings.QuietHours, settings.Timezone, now); err != nil {
+		m.logger.Warn("invalid quiet hours", "user", userID, "err", err)
+	} else if quiet {
+		return nil
+	}
+
+	if settings.LastNotifiedAt.Valid && now.Sub(settings.LastNotifiedAt.Time) < options.dmInterval() {
+		return nil
+	}
+
+	sightings := make([]sighting, 0, len(notifications))
+
+	for _, n := range notifications {
+		var s sighting
+
+		if err := json.Unmarshal([]byte(n.Data), &s); err != nil {
+			m.logger.Warn("could not parse watch notification", "user", userID, "err", err)
+			continue
+		}
+
+		sightings = append(sightings, s)
+	}
+
+	if len(sightings) > 0 {
+		channel, err := discord.UserChannelCreate(userID)
+
+		if err != nil {
+			return fmt.Errorf("error opening dm channel: %w", err)
+		}
+
+		_, err = m.outbox.Enqueue(ctx, mod.Delivery{
+			Module:    moduleName,
+			Kind:      moduleName,
+			ChannelID: channel.ID,
+			DedupeKey: fmt.Sprintf(
+				"%s:%s:%s:%d-%d",
+				moduleName,
+				options.ID,
+				userID,
+				sightings[0].ID,
+				sightings[len(sightings)-1].ID,
+			),
+			Message: mod.OutboxMessage{Embeds: []*discordgo.MessageEmbed{sightingsEmbed(sightings)}},
+		})
+
+		if err != nil {
+			return fmt.Errorf("error queueing dm: %w", err)
+		}
+
+		err = m.db.SaveWatchNotifiedAt(ctx, store.SaveWatchNotifiedAtParams{
+			DiscordUserID:  userID,
+			LastNotifiedAt: sql.NullTime{Time: now, Valid: true},
+		})
+
+		if err != nil {
+			return fmt.Errorf("error saving watch settings: %w", err)
+		}
+
+		m.logger.Info("sending watchlist dm", "user", userID, "sightings", len(sightings))
+	}
+
+	err = m.db.DeleteWatchNotifications(ctx, store.DeleteWatchNotificationsParams{
+		GuildID:       options.ID,
+		DiscordUserID: userID,
+	})
+
+	if err != nil {
+		return fmt.Errorf("error deleting watch notifications: %w", err)
+	}
+
+	return nil
+}
+
+func sightingsEmbed(sightings []sighting) *discordgo.MessageEmbed {
+	lines := make([]string, 0, maxDMItems+1)
+
+	for i, s := range sightings {
+		if i >= maxDMItems {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(sightings)-maxDMItems))
+			break
+		}
+
+		lines = append(lines, fmt.Sprintf(
+			"[%s](https://inaturalist.org/observations/%d) by %s, on your %s watch",
+			s.Taxon,
+			s.ID,
+			s.Observer,
+			s.Watch,
+		))
+	}
+
+	title := "Someone spotted something on your watchlist!"
+
+	if len(sightings) > 1 {
+		title = fmt.Sprintf("%d new sightings from your watchlist!", len(sightings))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       title,
+		Description: strings.Join(lines, "\n"),
+		Color:       embedColor,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "Use /unwatch to stop watching a taxon"},
+	}
+
+	for _, s := range sightings {
+		if s.PhotoURL != "" {
+			embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: s.PhotoURL}
+			embed.Footer.Text = fmt.Sprintf("Photo %s\n%s", s.Attribution, embed.Footer.Text)
+			break
+		}
+	}
+
+	return embed
+}
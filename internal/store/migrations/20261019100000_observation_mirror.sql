@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+create table observation (
+  id integer not null,
+  project_id integer not null,
+  user_id integer not null,
+  taxon_id integer not null default 0,
+  quality_grade text not null default '',
+  faves_count integer not null default 0,
+  photo_count integer not null default 0,
+  observed_on text not null default '',
+  data json not null default '{}',
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (project_id, id)
+);
+
+create index observation_project_user_idx on observation (project_id, user_id);
+
+create table observation_sync (
+  project_id integer primary key,
+  newest_id integer not null default 0,
+  oldest_id integer not null default 0,
+  backfill_complete boolean not null default false,
+  refreshed_at timestamp default current_timestamp not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table observation_sync;
+
+drop table observation;
+
+-- +goose StatementEnd
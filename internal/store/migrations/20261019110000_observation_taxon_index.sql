@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+create index observation_project_taxon_idx on observation (project_id, taxon_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop index observation_project_taxon_idx;
+
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+create table displayed_observer (
+  channel_id text not null,
+  project_id integer not null,
+  user_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, project_id, user_id)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table displayed_observer;
+
+-- +goose StatementEnd
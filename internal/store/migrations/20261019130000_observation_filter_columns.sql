@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table observation add column user_login text not null default '';
+
+alter table observation add column iconic_taxon text not null default '';
+
+alter table observation add column taxon_ancestry text not null default '';
+
+alter table observation add column place_ids text not null default '';
+
+-- the new columns can only be filled in from iNaturalist, so start the
+-- mirror over and let the sync job re-fetch everything
+delete from observation_sync;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+alter table observation drop column place_ids;
+
+alter table observation drop column taxon_ancestry;
+
+alter table observation drop column iconic_taxon;
+
+alter table observation drop column user_login;
+
+-- +goose StatementEnd
@@ -0,0 +1,102 @@
+-- +goose Up
+-- +goose StatementBegin
+create table seen_obs_copy (
+  id integer not null,
+  channel_id text not null,
+  project_id integer not null,
+  feed text not null default 'default',
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (id, channel_id, project_id, feed)
+);
+
+insert into seen_obs_copy (id, channel_id, project_id, created_at, updated_at)
+select
+  id,
+  channel_id,
+  project_id,
+  created_at,
+  updated_at
+from
+  seen_observation;
+
+drop table seen_observation;
+
+alter table seen_obs_copy rename to seen_observation;
+
+create table displayed_obs_copy (
+  channel_id text not null,
+  project_id integer not null,
+  feed text not null default 'default',
+  user_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, project_id, feed, user_id)
+);
+
+insert into displayed_obs_copy (channel_id, project_id, user_id, created_at, updated_at)
+select
+  channel_id,
+  project_id,
+  user_id,
+  created_at,
+  updated_at
+from
+  displayed_observer;
+
+drop table displayed_observer;
+
+alter table displayed_obs_copy rename to displayed_observer;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+create table seen_obs_copy (
+  id integer not null,
+  channel_id text not null,
+  project_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (id, channel_id, project_id)
+);
+
+insert
+  or ignore into seen_obs_copy (id, channel_id, project_id, created_at, updated_at)
+select
+  id,
+  channel_id,
+  project_id,
+  created_at,
+  updated_at
+from
+  seen_observation;
+
+drop table seen_observation;
+
+alter table seen_obs_copy rename to seen_observation;
+
+create table displayed_obs_copy (
+  channel_id text not null,
+  project_id integer not null,
+  user_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, project_id, user_id)
+);
+
+insert
+  or ignore into displayed_obs_copy (channel_id, project_id, user_id, created_at, updated_at)
+select
+  channel_id,
+  project_id,
+  user_id,
+  created_at,
+  updated_at
+from
+  displayed_observer;
+
+drop table displayed_observer;
+
+alter table displayed_obs_copy rename to displayed_observer;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+create table scheduled_job (
+  name text primary key,
+  module text not null,
+  schedule text not null,
+  timezone text not null default '',
+  catch_up text not null default '',
+  last_run_at timestamp,
+  last_status text not null default '',
+  last_error text not null default '',
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+create table job_run (
+  id integer primary key autoincrement,
+  job_name text not null,
+  started_at timestamp not null,
+  finished_at timestamp not null,
+  status text not null,
+  error text not null default '',
+  catch_up boolean not null default false
+);
+
+create index job_run_job_name_idx on job_run (job_name, id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table job_run;
+
+drop table scheduled_job;
+
+-- +goose StatementEnd
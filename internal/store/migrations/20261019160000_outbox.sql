@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+create table outbox_message (
+  id integer primary key autoincrement,
+  module text not null,
+  kind text not null,
+  channel_id text not null,
+  dedupe_key text not null default '',
+  payload text not null,
+  meta text not null default '',
+  status text not null default 'pending',
+  attempts integer not null default 0,
+  next_attempt_at timestamp not null,
+  last_error text not null default '',
+  message_id text not null default '',
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+create unique index outbox_message_dedupe_key_idx on outbox_message (dedupe_key)
+where
+  dedupe_key != '';
+
+create index outbox_message_status_idx on outbox_message (status, next_attempt_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table outbox_message;
+
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+-- mirrored observations are missing photo licenses and attribution, start
+-- the mirror over and let the sync job re-fetch everything
+delete from observation_sync;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+select
+  1;
+
+-- +goose StatementEnd
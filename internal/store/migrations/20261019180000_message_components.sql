@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+create table component_state (
+  id text primary key,
+  module text not null,
+  data text not null,
+  created_at timestamp default current_timestamp not null
+);
+
+create table cooldown (
+  key text primary key,
+  expires_at timestamp not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table cooldown;
+
+drop table component_state;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+create table announcement_mark (
+  channel_id text not null,
+  feed text not null,
+  project_id integer not null,
+  last_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, feed)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table announcement_mark;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+create table project_milestone (
+  channel_id text not null,
+  project_id integer not null,
+  observation_count integer not null default 0,
+  species_count integer not null default 0,
+  observer_count integer not null default 0,
+  last_observation_id integer not null default 0,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, project_id)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table project_milestone;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+create table leaderboard_snapshot (
+  channel_id text not null,
+  project_id integer not null,
+  period text not null,
+  period_start text not null,
+  data text not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (channel_id, project_id, period, period_start)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table leaderboard_snapshot;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+create table inat_account (
+  discord_user_id text primary key not null,
+  inat_user_id integer not null unique,
+  inat_login text not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+create table inat_account_verification (
+  discord_user_id text primary key not null,
+  inat_login text not null,
+  code text not null,
+  created_at timestamp default current_timestamp not null
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table inat_account_verification;
+
+drop table inat_account;
+
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+create table taxon_watch (
+  guild_id text not null,
+  discord_user_id text not null,
+  taxon_id integer not null,
+  taxon_name text not null,
+  created_at timestamp default current_timestamp not null,
+  primary key (guild_id, discord_user_id, taxon_id)
+);
+
+create table watch_settings (
+  discord_user_id text primary key not null,
+  quiet_hours text not null default '',
+  timezone text not null default '',
+  last_notified_at timestamp,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+create table watch_notification (
+  guild_id text not null,
+  discord_user_id text not null,
+  observation_id integer not null,
+  data text not null,
+  created_at timestamp default current_timestamp not null,
+  primary key (guild_id, discord_user_id, observation_id)
+);
+
+create table watch_mark (
+  guild_id text primary key not null,
+  project_id integer not null,
+  last_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table watch_mark;
+
+drop table watch_notification;
+
+drop table watch_settings;
+
+drop table taxon_watch;
+
+-- +goose StatementEnd
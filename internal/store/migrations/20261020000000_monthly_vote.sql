@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+create table ballot (
+  id integer primary key,
+  channel_id text not null,
+  period text not null,
+  message_id text not null default '',
+  candidates text not null,
+  closed_at timestamp,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  unique (channel_id, period)
+);
+
+create table ballot_vote (
+  ballot_id integer not null,
+  discord_user_id text not null,
+  observation_id integer not null,
+  created_at timestamp default current_timestamp not null,
+  updated_at timestamp default current_timestamp not null,
+  primary key (ballot_id, discord_user_id)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table ballot_vote;
+
+drop table ballot;
+
+-- +goose StatementEnd
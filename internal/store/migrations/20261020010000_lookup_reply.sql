@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+create table lookup_reply (
+  message_id text primary key not null,
+  channel_id text not null,
+  reply_id text not null,
+  author_id text not null,
+  content text not null,
+  created_at timestamp default current_timestamp not null
+);
+
+create index lookup_reply_reply_id_idx on lookup_reply (reply_id);
+
+create index lookup_reply_created_at_idx on lookup_reply (created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+drop table lookup_reply;
+
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+update outbox_message
+set
+  dedupe_key = ''
+where
+  status = 'failed';
+
+-- +goose StatementEnd
+-- +goose Down
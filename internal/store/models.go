@@ -5,9 +5,61 @@
 package store
 
 import (
+	"database/sql"
 	"time"
 )
 
+type AnnouncementMark struct {
+	ChannelID string    `json:"channel_id"`
+	Feed      string    `json:"feed"`
+	ProjectID int64     `json:"project_id"`
+	LastID    int64     `json:"last_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Ballot struct {
+	ID         int64        `json:"id"`
+	ChannelID  string       `json:"channel_id"`
+	Period     string       `json:"period"`
+	MessageID  string       `json:"message_id"`
+	Candidates string       `json:"candidates"`
+	ClosedAt   sql.NullTime `json:"closed_at"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+type BallotVote struct {
+	BallotID      int64     `json:"ballot_id"`
+	DiscordUserID string    `json:"discord_user_id"`
+	ObservationID int64     `json:"observation_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type ComponentState struct {
+	ID        string    `json:"id"`
+	Module    string    `json:"module"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Cooldown struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type DisplayedObserver struct {
+	ChannelID string    `json:"channel_id"`
+	ProjectID int64     `json:"project_id"`
+	Feed      string    `json:"feed"`
+	UserID    int64     `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type FeaturedMessage struct {
 	GuildID   string    `json:"guild_id"`
 	ChannelID string    `json:"channel_id"`
@@ -16,16 +68,163 @@ type FeaturedMessage struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+type InatAccount struct {
+	DiscordUserID string    `json:"discord_user_id"`
+	InatUserID    int64     `json:"inat_user_id"`
+	InatLogin     string    `json:"inat_login"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type InatAccountVerification struct {
+	DiscordUserID string    `json:"discord_user_id"`
+	InatLogin     string    `json:"inat_login"`
+	Code          string    `json:"code"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type JobRun struct {
+	ID         int64     `json:"id"`
+	JobName    string    `json:"job_name"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error"`
+	CatchUp    bool      `json:"catch_up"`
+}
+
+type LeaderboardSnapshot struct {
+	ChannelID   string    `json:"channel_id"`
+	ProjectID   int64     `json:"project_id"`
+	Period      string    `json:"period"`
+	PeriodStart string    `json:"period_start"`
+	Data        string    `json:"data"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type LookupReply struct {
+	MessageID string    `json:"message_id"`
+	ChannelID string    `json:"channel_id"`
+	ReplyID   string    `json:"reply_id"`
+	AuthorID  string    `json:"author_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type ModuleConfiguration struct {
 	Module string      `json:"module"`
 	Key    string      `json:"key"`
 	Data   interface{} `json:"data"`
 }
 
+type Observation struct {
+	ID            int64       `json:"id"`
+	ProjectID     int64       `json:"project_id"`
+	UserID        int64       `json:"user_id"`
+	TaxonID       int64       `json:"taxon_id"`
+	QualityGrade  string      `json:"quality_grade"`
+	FavesCount    int64       `json:"faves_count"`
+	PhotoCount    int64       `json:"photo_count"`
+	ObservedOn    string      `json:"observed_on"`
+	Data          interface{} `json:"data"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	UserLogin     string      `json:"user_login"`
+	IconicTaxon   string      `json:"iconic_taxon"`
+	TaxonAncestry string      `json:"taxon_ancestry"`
+	PlaceIds      string      `json:"place_ids"`
+}
+
+type ObservationSync struct {
+	ProjectID        int64     `json:"project_id"`
+	NewestID         int64     `json:"newest_id"`
+	OldestID         int64     `json:"oldest_id"`
+	BackfillComplete bool      `json:"backfill_complete"`
+	RefreshedAt      time.Time `json:"refreshed_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type OutboxMessage struct {
+	ID            int64     `json:"id"`
+	Module        string    `json:"module"`
+	Kind          string    `json:"kind"`
+	ChannelID     string    `json:"channel_id"`
+	DedupeKey     string    `json:"dedupe_key"`
+	Payload       string    `json:"payload"`
+	Meta          string    `json:"meta"`
+	Status        string    `json:"status"`
+	Attempts      int64     `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+	MessageID     string    `json:"message_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type ProjectMilestone struct {
+	ChannelID         string    `json:"channel_id"`
+	ProjectID         int64     `json:"project_id"`
+	ObservationCount  int64     `json:"observation_count"`
+	SpeciesCount      int64     `json:"species_count"`
+	ObserverCount     int64     `json:"observer_count"`
+	LastObservationID int64     `json:"last_observation_id"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type ScheduledJob struct {
+	Name       string       `json:"name"`
+	Module     string       `json:"module"`
+	Schedule   string       `json:"schedule"`
+	Timezone   string       `json:"timezone"`
+	CatchUp    string       `json:"catch_up"`
+	LastRunAt  sql.NullTime `json:"last_run_at"`
+	LastStatus string       `json:"last_status"`
+	LastError  string       `json:"last_error"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
 type SeenObservation struct {
 	ID        int64     `json:"id"`
 	ChannelID string    `json:"channel_id"`
 	ProjectID int64     `json:"project_id"`
+	Feed      string    `json:"feed"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TaxonWatch struct {
+	GuildID       string    `json:"guild_id"`
+	DiscordUserID string    `json:"discord_user_id"`
+	TaxonID       int64     `json:"taxon_id"`
+	TaxonName     string    `json:"taxon_name"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type WatchMark struct {
+	GuildID   string    `json:"guild_id"`
+	ProjectID int64     `json:"project_id"`
+	LastID    int64     `json:"last_id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type WatchNotification struct {
+	GuildID       string    `json:"guild_id"`
+	DiscordUserID string    `json:"discord_user_id"`
+	ObservationID int64     `json:"observation_id"`
+	Data          string    `json:"data"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type WatchSetting struct {
+	DiscordUserID  string       `json:"discord_user_id"`
+	QuietHours     string       `json:"quiet_hours"`
+	Timezone       string       `json:"timezone"`
+	LastNotifiedAt sql.NullTime `json:"last_notified_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
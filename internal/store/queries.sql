@@ -1,17 +1,7 @@
--- name: FindObservations :many
-select
-  *
-from
-  seen_observation
-where
-  project_id = sqlc.arg ('project_id')
-  and channel_id = sqlc.arg ('channel_id')
-  and id in (sqlc.slice ('id'));
-
 -- name: CreateSeenObservation :one
 insert
-  or ignore into seen_observation (id, channel_id, project_id)
-    values (?, ?, ?)
+  or ignore into seen_observation (id, channel_id, project_id, feed)
+    values (?, ?, ?, ?)
   returning
     *;
 
@@ -75,3 +65,642 @@ where module = ?
   and key = ?
 returning
   *;
+
+-- name: UpsertObservation :exec
+insert into observation (id, project_id, user_id, user_login, taxon_id,
+  iconic_taxon, taxon_ancestry, place_ids, quality_grade, faves_count,
+  photo_count, observed_on, data)
+  values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+on conflict (project_id, id)
+  do update set
+    user_id = excluded.user_id, user_login = excluded.user_login, taxon_id =
+      excluded.taxon_id, iconic_taxon = excluded.iconic_taxon, taxon_ancestry =
+      excluded.taxon_ancestry, place_ids = excluded.place_ids, quality_grade =
+      excluded.quality_grade, faves_count = excluded.faves_count, photo_count =
+      excluded.photo_count, observed_on = excluded.observed_on, data =
+      excluded.data, updated_at = current_timestamp;
+
+-- name: FindUnseenObservationCandidates :many
+-- list filters are json arrays, an empty array matches everything
+with filter as (
+  select
+    cast(sqlc.arg ('taxon_ids') as text) as taxon_ids,
+    cast(sqlc.arg ('iconic_taxa') as text) as iconic_taxa,
+    cast(sqlc.arg ('quality_grades') as text) as quality_grades,
+    cast(sqlc.arg ('place_ids') as text) as place_ids,
+    cast(sqlc.arg ('excluded_users') as text) as excluded_users,
+    cast(sqlc.arg ('observed_after') as text) as observed_after,
+    cast(sqlc.arg ('observed_before') as text) as observed_before,
+    cast(sqlc.arg ('photos') as text) as photos,
+    cast(sqlc.arg ('min_faves') as integer) as min_faves,
+    cast(sqlc.arg ('user_login') as text) as user_login,
+    cast(sqlc.arg ('taxon_id') as integer) as taxon_id
+),
+taxon_count as (
+  select
+    taxon_id,
+    count(*) as taxon_count
+  from
+    observation
+  where
+    project_id = sqlc.arg ('project_id')
+  group by
+    taxon_id
+)
+select
+  o.id,
+  o.user_id,
+  o.user_login,
+  o.taxon_id,
+  o.iconic_taxon,
+  o.taxon_ancestry,
+  o.place_ids,
+  o.quality_grade,
+  o.faves_count,
+  o.photo_count,
+  o.observed_on,
+  cast(coalesce(t.taxon_count, 0) as integer) as taxon_count
+from
+  observation o
+  cross join filter
+  left join taxon_count t on t.taxon_id = o.taxon_id
+  left join seen_observation s on s.id = o.id
+    and s.project_id = o.project_id
+    and s.channel_id = sqlc.arg ('channel_id')
+    and s.feed = sqlc.arg ('feed')
+where
+  o.project_id = sqlc.arg ('project_id')
+  and s.id is null
+  and (filter.taxon_ids = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.taxon_ids) f
+      where
+        ',' || o.taxon_ancestry || ',' like '%,' || f.value || ',%'))
+  and (filter.iconic_taxa = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.iconic_taxa) f
+      where
+        lower(f.value) = lower(o.iconic_taxon)))
+  and (filter.quality_grades = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.quality_grades) f
+      where
+        f.value = o.quality_grade))
+  and (filter.place_ids = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.place_ids) f
+      where
+        ',' || o.place_ids || ',' like '%,' || f.value || ',%'))
+  and not exists (
+    select
+      1
+    from
+      json_each(filter.excluded_users) f
+    where
+      lower(f.value) = lower(o.user_login)
+      or f.value = cast(o.user_id as text))
+  -- observed_on is stored as YYYY-MM-DD, so the dates compare as strings
+  and (filter.observed_after = ''
+    or (o.observed_on != ''
+      and o.observed_on >= filter.observed_after))
+  and (filter.observed_before = ''
+    or (o.observed_on != ''
+      and o.observed_on <= filter.observed_before))
+  and (filter.photos = 'any'
+    or (filter.photos = 'true'
+      and o.photo_count > 0)
+    or (filter.photos = 'false'
+      and o.photo_count <= 0))
+  and o.faves_count >= filter.min_faves
+  and (filter.user_login = ''
+    or lower(o.user_login) = lower(filter.user_login))
+  and (filter.taxon_id = 0
+    or ',' || o.taxon_ancestry || ',' like '%,' || filter.taxon_id || ',%');
+
+-- name: FindProjectObservation :one
+select
+  *
+from
+  observation
+where
+  project_id = ?
+  and id = ?;
+
+-- name: DeleteProjectObservation :exec
+delete from observation
+where project_id = ?
+  and id = ?;
+
+-- name: FindObservationSync :one
+select
+  *
+from
+  observation_sync
+where
+  project_id = ?;
+
+-- name: SaveObservationSync :one
+insert into observation_sync (project_id, newest_id, oldest_id,
+  backfill_complete, refreshed_at)
+  values (?, ?, ?, ?, ?)
+on conflict (project_id)
+  do update set
+    newest_id = excluded.newest_id, oldest_id = excluded.oldest_id,
+      backfill_complete = excluded.backfill_complete, refreshed_at =
+      excluded.refreshed_at, updated_at = current_timestamp
+  returning
+    *;
+
+-- name: FindDisplayedObservers :many
+select
+  *
+from
+  displayed_observer
+where (channel_id = sqlc.arg ('channel_id')
+  or sqlc.arg ('channel_id') = '')
+and (project_id = sqlc.arg ('project_id')
+  or sqlc.arg ('project_id') = 0)
+and (feed = sqlc.arg ('feed')
+  or sqlc.arg ('feed') = '')
+order by
+  channel_id,
+  project_id,
+  feed,
+  created_at;
+
+-- name: CreateDisplayedObserver :exec
+insert
+  or ignore into displayed_observer (channel_id, project_id, feed, user_id)
+    values (?, ?, ?, ?);
+
+-- name: DeleteDisplayedObservers :exec
+delete from displayed_observer
+where channel_id = ?
+  and project_id = ?
+  and feed = ?;
+
+-- name: FindScheduledJob :one
+select
+  *
+from
+  scheduled_job
+where
+  name = ?;
+
+-- name: FindScheduledJobs :many
+select
+  *
+from
+  scheduled_job
+order by
+  name;
+
+-- name: SaveScheduledJob :one
+insert into scheduled_job (name, module, schedule, timezone, catch_up)
+  values (?, ?, ?, ?, ?)
+on conflict (name)
+  do update set
+    module = excluded.module, schedule = excluded.schedule, timezone =
+      excluded.timezone, catch_up = excluded.catch_up, updated_at = current_timestamp
+  returning
+    *;
+
+-- name: UpdateScheduledJobLastRun :exec
+update
+  scheduled_job
+set
+  last_run_at = ?,
+  last_status = ?,
+  last_error = ?,
+  updated_at = current_timestamp
+where
+  name = ?;
+
+-- name: CreateJobRun :exec
+insert into job_run (job_name, started_at, finished_at, status, error, catch_up)
+  values (?, ?, ?, ?, ?, ?);
+
+-- name: FindJobRuns :many
+select
+  *
+from
+  job_run
+where
+  job_name = ?
+order by
+  id desc
+limit ?;
+
+-- name: DeleteOldJobRuns :exec
+delete from job_run
+where job_run.job_name = sqlc.arg ('job_name')
+  and job_run.id not in (
+    select
+      r.id
+    from
+      job_run r
+    where
+      r.job_name = sqlc.arg ('job_name')
+    order by
+      r.id desc
+    limit sqlc.arg ('keep'));
+
+-- name: CreateOutboxMessage :execrows
+insert
+  or ignore into outbox_message (module, kind, channel_id, dedupe_key, payload, meta, next_attempt_at)
+    values (?, ?, ?, ?, ?, ?, ?);
+
+-- name: FindDueOutboxMessages :many
+select
+  *
+from
+  outbox_message
+where
+  status = 'pending'
+  and next_attempt_at <= sqlc.arg ('now')
+order by
+  id
+limit sqlc.arg ('limit');
+
+-- name: UpdateOutboxMessageAttempt :exec
+update
+  outbox_message
+set
+  status = ?,
+  attempts = ?,
+  next_attempt_at = ?,
+  last_error = ?,
+  message_id = ?,
+  dedupe_key = ?,
+  updated_at = ?
+where
+  id = ?;
+
+-- name: DeleteDeliveredOutboxMessages :exec
+delete from outbox_message
+where status != 'pending'
+  and updated_at < ?;
+
+-- name: CreateComponentState :exec
+insert into component_state (id, module, data)
+  values (?, ?, ?);
+
+-- name: FindComponentState :one
+select
+  *
+from
+  component_state
+where
+  id = ?;
+
+-- name: DeleteComponentStates :exec
+delete from component_state
+where module = ?
+  and created_at < ?;
+
+-- name: TakeCooldown :execrows
+-- created_at is the current time, an existing cooldown is only replaced once
+-- it has expired
+insert into cooldown (key, expires_at, created_at)
+  values (?, ?, ?)
+on conflict (key)
+  do update set
+    expires_at = excluded.expires_at, updated_at = current_timestamp
+  where
+    cooldown.expires_at <= excluded.created_at;
+
+-- name: DeleteCooldown :exec
+delete from cooldown
+where key = ?;
+
+-- name: FindCooldown :one
+select
+  *
+from
+  cooldown
+where
+  key = ?;
+
+-- name: FindAnnouncementMark :one
+select
+  *
+from
+  announcement_mark
+where
+  channel_id = ?
+  and feed = ?;
+
+-- name: SaveAnnouncementMark :exec
+insert into announcement_mark (channel_id, feed, project_id, last_id)
+  values (?, ?, ?, ?)
+on conflict (channel_id, feed)
+  do update set
+    project_id = excluded.project_id, last_id = excluded.last_id, updated_at = current_timestamp;
+
+-- name: FindProjectMilestone :one
+select
+  *
+from
+  project_milestone
+where
+  channel_id = ?
+  and project_id = ?;
+
+-- name: SaveProjectMilestone :exec
+insert into project_milestone (channel_id, project_id, observation_count, species_count,
+  observer_count, last_observation_id)
+  values (?, ?, ?, ?, ?, ?)
+on conflict (channel_id, project_id)
+  do update set
+    observation_count = excluded.observation_count, species_count =
+      excluded.species_count, observer_count = excluded.observer_count,
+      last_observation_id = excluded.last_observation_id, updated_at = current_timestamp;
+
+-- name: FindLeaderboardSnapshot :one
+select
+  *
+from
+  leaderboard_snapshot
+where
+  channel_id = ?
+  and project_id = ?
+  and period = ?
+  and period_start = ?;
+
+-- name: SaveLeaderboardSnapshot :exec
+insert into leaderboard_snapshot (channel_id, project_id, period, period_start, data)
+  values (?, ?, ?, ?, ?)
+on conflict (channel_id, project_id, period, period_start)
+  do update set
+    data = excluded.data, updated_at = current_timestamp;
+
+-- name: FindInatAccount :one
+select
+  *
+from
+  inat_account
+where
+  discord_user_id = ?;
+
+-- name: FindInatAccountByInatUser :one
+select
+  *
+from
+  inat_account
+where
+  inat_user_id = ?;
+
+-- name: SaveInatAccount :exec
+insert into inat_account (discord_user_id, inat_user_id, inat_login)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    inat_user_id = excluded.inat_user_id, inat_login = excluded.inat_login, updated_at = current_timestamp;
+
+-- name: DeleteInatAccount :execrows
+delete from inat_account
+where discord_user_id = ?;
+
+-- name: DeleteInatAccountByInatUser :exec
+delete from inat_account
+where inat_user_id = ?;
+
+-- name: FindInatAccountVerification :one
+select
+  *
+from
+  inat_account_verification
+where
+  discord_user_id = ?;
+
+-- name: SaveInatAccountVerification :exec
+insert into inat_account_verification (discord_user_id, inat_login, code)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    inat_login = excluded.inat_login, code = excluded.code, created_at = current_timestamp;
+
+-- name: DeleteInatAccountVerification :exec
+delete from inat_account_verification
+where discord_user_id = ?;
+
+-- name: ListInatAccounts :many
+select
+  *
+from
+  inat_account
+order by
+  discord_user_id;
+
+-- name: CreateTaxonWatch :execrows
+insert or ignore into taxon_watch (guild_id, discord_user_id, taxon_id, taxon_name)
+  values (?, ?, ?, ?);
+
+-- name: DeleteTaxonWatch :execrows
+delete from taxon_watch
+where guild_id = ?
+  and discord_user_id = ?
+  and taxon_id = ?;
+
+-- name: ListUserTaxonWatches :many
+select
+  *
+from
+  taxon_watch
+where
+  guild_id = ?
+  and discord_user_id = ?
+order by
+  taxon_name;
+
+-- name: ListGuildTaxonWatches :many
+select
+  *
+from
+  taxon_watch
+where
+  guild_id = ?;
+
+-- name: FindWatchSettings :one
+select
+  *
+from
+  watch_settings
+where
+  discord_user_id = ?;
+
+-- name: SaveWatchSettings :exec
+insert into watch_settings (discord_user_id, quiet_hours, timezone)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    quiet_hours = excluded.quiet_hours, timezone = excluded.timezone, updated_at = current_timestamp;
+
+-- name: SaveWatchNotifiedAt :exec
+insert into watch_settings (discord_user_id, last_notified_at)
+  values (?, ?)
+on conflict (discord_user_id)
+  do update set
+    last_notified_at = excluded.last_notified_at, updated_at = current_timestamp;
+
+-- name: CreateWatchNotification :exec
+insert or ignore into watch_notification (guild_id, discord_user_id, observation_id, data)
+  values (?, ?, ?, ?);
+
+-- name: ListWatchNotifications :many
+select
+  *
+from
+  watch_notification
+where
+  guild_id = ?
+order by
+  discord_user_id,
+  observation_id;
+
+-- name: DeleteWatchNotifications :exec
+delete from watch_notification
+where guild_id = ?
+  and discord_user_id = ?;
+
+-- name: FindWatchMark :one
+select
+  *
+from
+  watch_mark
+where
+  guild_id = ?;
+
+-- name: SaveWatchMark :exec
+insert into watch_mark (guild_id, project_id, last_id)
+  values (?, ?, ?)
+on conflict (guild_id)
+  do update set
+    project_id = excluded.project_id, last_id = excluded.last_id, updated_at = current_timestamp;
+
+-- name: ListPostedObservations :many
+select
+  *
+from
+  seen_observation
+where
+  channel_id = sqlc.arg ('channel_id')
+  and created_at >= sqlc.arg ('start')
+  and created_at < sqlc.arg ('end')
+order by
+  id;
+
+-- name: CreateBallot :execrows
+insert or ignore into ballot (channel_id, period, candidates)
+  values (?, ?, ?);
+
+-- name: FindBallot :one
+select
+  *
+from
+  ballot
+where
+  channel_id = ?
+  and period = ?;
+
+-- name: FindBallotByID :one
+select
+  *
+from
+  ballot
+where
+  id = ?;
+
+-- name: FindOpenBallot :one
+select
+  *
+from
+  ballot
+where
+  channel_id = ?
+  and closed_at is null
+order by
+  id desc
+limit 1;
+
+-- name: SaveBallotMessage :exec
+update
+  ballot
+set
+  message_id = ?,
+  updated_at = current_timestamp
+where
+  id = ?;
+
+-- name: CloseBallot :exec
+update
+  ballot
+set
+  closed_at = current_timestamp,
+  updated_at = current_timestamp
+where
+  id = ?;
+
+-- name: SaveBallotVote :exec
+insert into ballot_vote (ballot_id, discord_user_id, observation_id)
+  values (?, ?, ?)
+on conflict (ballot_id, discord_user_id)
+  do update set
+    observation_id = excluded.observation_id, updated_at = current_timestamp;
+
+-- name: CountBallotVotes :many
+select
+  observation_id,
+  count(*) as votes
+from
+  ballot_vote
+where
+  ballot_id = ?
+group by
+  observation_id
+order by
+  votes desc,
+  max(updated_at) asc;
+
+-- name: SaveLookupReply :exec
+insert into lookup_reply (message_id, channel_id, reply_id, author_id, content)
+  values (?, ?, ?, ?, ?)
+on conflict (message_id)
+  do update set
+    reply_id = excluded.reply_id, content = excluded.content;
+
+-- name: FindLookupReply :one
+select
+  *
+from
+  lookup_reply
+where
+  message_id = ?;
+
+-- name: FindLookupReplyByReply :one
+select
+  *
+from
+  lookup_reply
+where
+  reply_id = ?;
+
+-- name: DeleteLookupReply :exec
+delete from lookup_reply
+where message_id = ?;
+
+-- name: DeleteOldLookupReplies :exec
+delete from lookup_reply
+where created_at < ?;
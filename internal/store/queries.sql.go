@@ -7,9 +7,152 @@ package store
 
 import (
 	"context"
-	"strings"
+	"database/sql"
+	"time"
 )
 
+const closeBallot = `-- name: CloseBallot :exec
+update
+  ballot
+set
+  closed_at = current_timestamp,
+  updated_at = current_timestamp
+where
+  id = ?
+`
+
+func (q *Queries) CloseBallot(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, closeBallot, id)
+	return err
+}
+
+const countBallotVotes = `-- name: CountBallotVotes :many
+select
+  observation_id,
+  count(*) as votes
+from
+  ballot_vote
+where
+  ballot_id = ?
+group by
+  observation_id
+order by
+  votes desc,
+  max(updated_at) asc
+`
+
+type CountBallotVotesRow struct {
+	ObservationID int64 `json:"observation_id"`
+	Votes         int64 `json:"votes"`
+}
+
+func (q *Queries) CountBallotVotes(ctx context.Context, ballotID int64) ([]CountBallotVotesRow, error) {
+	rows, err := q.db.QueryContext(ctx, countBallotVotes, ballotID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountBallotVotesRow
+	for rows.Next() {
+		var i CountBallotVotesRow
+		if err := rows.Scan(&i.ObservationID, &i.Votes); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createBallot = `-- name: CreateBallot :execrows
+insert or ignore into ballot (channel_id, period, candidates)
+  values (?, ?, ?)
+`
+
+type CreateBallotParams struct {
+	ChannelID  string `json:"channel_id"`
+	Period     string `json:"period"`
+	Candidates string `json:"candidates"`
+}
+
+func (q *Queries) CreateBallot(ctx context.Context, arg CreateBallotParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createBallot, arg.ChannelID, arg.Period, arg.Candidates)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createComponentState = `-- name: CreateComponentState :exec
+insert into component_state (id, module, data)
+  values (?, ?, ?)
+`
+
+type CreateComponentStateParams struct {
+	ID     string `json:"id"`
+	Module string `json:"module"`
+	Data   string `json:"data"`
+}
+
+func (q *Queries) CreateComponentState(ctx context.Context, arg CreateComponentStateParams) error {
+	_, err := q.db.ExecContext(ctx, createComponentState, arg.ID, arg.Module, arg.Data)
+	return err
+}
+
+const createDisplayedObserver = `-- name: CreateDisplayedObserver :exec
+insert
+  or ignore into displayed_observer (channel_id, project_id, feed, user_id)
+    values (?, ?, ?, ?)
+`
+
+type CreateDisplayedObserverParams struct {
+	ChannelID string `json:"channel_id"`
+	ProjectID int64  `json:"project_id"`
+	Feed      string `json:"feed"`
+	UserID    int64  `json:"user_id"`
+}
+
+func (q *Queries) CreateDisplayedObserver(ctx context.Context, arg CreateDisplayedObserverParams) error {
+	_, err := q.db.ExecContext(ctx, createDisplayedObserver,
+		arg.ChannelID,
+		arg.ProjectID,
+		arg.Feed,
+		arg.UserID,
+	)
+	return err
+}
+
+const createJobRun = `-- name: CreateJobRun :exec
+insert into job_run (job_name, started_at, finished_at, status, error, catch_up)
+  values (?, ?, ?, ?, ?, ?)
+`
+
+type CreateJobRunParams struct {
+	JobName    string    `json:"job_name"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error"`
+	CatchUp    bool      `json:"catch_up"`
+}
+
+func (q *Queries) CreateJobRun(ctx context.Context, arg CreateJobRunParams) error {
+	_, err := q.db.ExecContext(ctx, createJobRun,
+		arg.JobName,
+		arg.StartedAt,
+		arg.FinishedAt,
+		arg.Status,
+		arg.Error,
+		arg.CatchUp,
+	)
+	return err
+}
+
 const createModuleConfiguration = `-- name: CreateModuleConfiguration :one
 insert into module_configuration (module, key, data)
   values (?, ?, ?)
@@ -30,33 +173,217 @@ func (q *Queries) CreateModuleConfiguration(ctx context.Context, arg CreateModul
 	return i, err
 }
 
+const createOutboxMessage = `-- name: CreateOutboxMessage :execrows
+insert
+  or ignore into outbox_message (module, kind, channel_id, dedupe_key, payload, meta, next_attempt_at)
+    values (?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateOutboxMessageParams struct {
+	Module        string    `json:"module"`
+	Kind          string    `json:"kind"`
+	ChannelID     string    `json:"channel_id"`
+	DedupeKey     string    `json:"dedupe_key"`
+	Payload       string    `json:"payload"`
+	Meta          string    `json:"meta"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+func (q *Queries) CreateOutboxMessage(ctx context.Context, arg CreateOutboxMessageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createOutboxMessage,
+		arg.Module,
+		arg.Kind,
+		arg.ChannelID,
+		arg.DedupeKey,
+		arg.Payload,
+		arg.Meta,
+		arg.NextAttemptAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const createSeenObservation = `-- name: CreateSeenObservation :one
 insert
-  or ignore into seen_observation (id, channel_id, project_id)
-    values (?, ?, ?)
+  or ignore into seen_observation (id, channel_id, project_id, feed)
+    values (?, ?, ?, ?)
   returning
-    id, channel_id, project_id, created_at, updated_at
+    id, channel_id, project_id, feed, created_at, updated_at
 `
 
 type CreateSeenObservationParams struct {
 	ID        int64  `json:"id"`
 	ChannelID string `json:"channel_id"`
 	ProjectID int64  `json:"project_id"`
+	Feed      string `json:"feed"`
 }
 
 func (q *Queries) CreateSeenObservation(ctx context.Context, arg CreateSeenObservationParams) (SeenObservation, error) {
-	row := q.db.QueryRowContext(ctx, createSeenObservation, arg.ID, arg.ChannelID, arg.ProjectID)
+	row := q.db.QueryRowContext(ctx, createSeenObservation,
+		arg.ID,
+		arg.ChannelID,
+		arg.ProjectID,
+		arg.Feed,
+	)
 	var i SeenObservation
 	err := row.Scan(
 		&i.ID,
 		&i.ChannelID,
 		&i.ProjectID,
+		&i.Feed,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
+const createTaxonWatch = `-- name: CreateTaxonWatch :execrows
+insert or ignore into taxon_watch (guild_id, discord_user_id, taxon_id, taxon_name)
+  values (?, ?, ?, ?)
+`
+
+type CreateTaxonWatchParams struct {
+	GuildID       string `json:"guild_id"`
+	DiscordUserID string `json:"discord_user_id"`
+	TaxonID       int64  `json:"taxon_id"`
+	TaxonName     string `json:"taxon_name"`
+}
+
+func (q *Queries) CreateTaxonWatch(ctx context.Context, arg CreateTaxonWatchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createTaxonWatch,
+		arg.GuildID,
+		arg.DiscordUserID,
+		arg.TaxonID,
+		arg.TaxonName,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createWatchNotification = `-- name: CreateWatchNotification :exec
+insert or ignore into watch_notification (guild_id, discord_user_id, observation_id, data)
+  values (?, ?, ?, ?)
+`
+
+type CreateWatchNotificationParams struct {
+	GuildID       string `json:"guild_id"`
+	DiscordUserID string `json:"discord_user_id"`
+	ObservationID int64  `json:"observation_id"`
+	Data          string `json:"data"`
+}
+
+func (q *Queries) CreateWatchNotification(ctx context.Context, arg CreateWatchNotificationParams) error {
+	_, err := q.db.ExecContext(ctx, createWatchNotification,
+		arg.GuildID,
+		arg.DiscordUserID,
+		arg.ObservationID,
+		arg.Data,
+	)
+	return err
+}
+
+const deleteComponentStates = `-- name: DeleteComponentStates :exec
+delete from component_state
+where module = ?
+  and created_at < ?
+`
+
+type DeleteComponentStatesParams struct {
+	Module    string    `json:"module"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) DeleteComponentStates(ctx context.Context, arg DeleteComponentStatesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteComponentStates, arg.Module, arg.CreatedAt)
+	return err
+}
+
+const deleteCooldown = `-- name: DeleteCooldown :exec
+delete from cooldown
+where key = ?
+`
+
+func (q *Queries) DeleteCooldown(ctx context.Context, key string) error {
+	_, err := q.db.ExecContext(ctx, deleteCooldown, key)
+	return err
+}
+
+const deleteDeliveredOutboxMessages = `-- name: DeleteDeliveredOutboxMessages :exec
+delete from outbox_message
+where status != 'pending'
+  and updated_at < ?
+`
+
+func (q *Queries) DeleteDeliveredOutboxMessages(ctx context.Context, updatedAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteDeliveredOutboxMessages, updatedAt)
+	return err
+}
+
+const deleteDisplayedObservers = `-- name: DeleteDisplayedObservers :exec
+delete from displayed_observer
+where channel_id = ?
+  and project_id = ?
+  and feed = ?
+`
+
+type DeleteDisplayedObserversParams struct {
+	ChannelID string `json:"channel_id"`
+	ProjectID int64  `json:"project_id"`
+	Feed      string `json:"feed"`
+}
+
+func (q *Queries) DeleteDisplayedObservers(ctx context.Context, arg DeleteDisplayedObserversParams) error {
+	_, err := q.db.ExecContext(ctx, deleteDisplayedObservers, arg.ChannelID, arg.ProjectID, arg.Feed)
+	return err
+}
+
+const deleteInatAccount = `-- name: DeleteInatAccount :execrows
+delete from inat_account
+where discord_user_id = ?
+`
+
+func (q *Queries) DeleteInatAccount(ctx context.Context, discordUserID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteInatAccount, discordUserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteInatAccountByInatUser = `-- name: DeleteInatAccountByInatUser :exec
+delete from inat_account
+where inat_user_id = ?
+`
+
+func (q *Queries) DeleteInatAccountByInatUser(ctx context.Context, inatUserID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteInatAccountByInatUser, inatUserID)
+	return err
+}
+
+const deleteInatAccountVerification = `-- name: DeleteInatAccountVerification :exec
+delete from inat_account_verification
+where discord_user_id = ?
+`
+
+func (q *Queries) DeleteInatAccountVerification(ctx context.Context, discordUserID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInatAccountVerification, discordUserID)
+	return err
+}
+
+const deleteLookupReply = `-- name: DeleteLookupReply :exec
+delete from lookup_reply
+where message_id = ?
+`
+
+func (q *Queries) DeleteLookupReply(ctx context.Context, messageID string) error {
+	_, err := q.db.ExecContext(ctx, deleteLookupReply, messageID)
+	return err
+}
+
 const deleteModuleConfiguration = `-- name: DeleteModuleConfiguration :one
 delete from module_configuration
 where module = ?
@@ -77,74 +404,1103 @@ func (q *Queries) DeleteModuleConfiguration(ctx context.Context, arg DeleteModul
 	return i, err
 }
 
-const findIsMessageFeatured = `-- name: FindIsMessageFeatured :one
-select
-  exists (
+const deleteOldJobRuns = `-- name: DeleteOldJobRuns :exec
+delete from job_run
+where job_run.job_name = ?1
+  and job_run.id not in (
     select
-      1
+      r.id
     from
-      featured_message
+      job_run r
     where
-      channel_id = ?
-      and message_id = ?
-      and guild_id = ?
-    limit 1)
+      r.job_name = ?1
+    order by
+      r.id desc
+    limit ?2)
 `
 
-type FindIsMessageFeaturedParams struct {
+type DeleteOldJobRunsParams struct {
+	JobName string `json:"job_name"`
+	Keep    int64  `json:"keep"`
+}
+
+func (q *Queries) DeleteOldJobRuns(ctx context.Context, arg DeleteOldJobRunsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOldJobRuns, arg.JobName, arg.Keep)
+	return err
+}
+
+const deleteOldLookupReplies = `-- name: DeleteOldLookupReplies :exec
+delete from lookup_reply
+where created_at < ?
+`
+
+func (q *Queries) DeleteOldLookupReplies(ctx context.Context, createdAt time.Time) error {
+	_, err := q.db.ExecContext(ctx, deleteOldLookupReplies, createdAt)
+	return err
+}
+
+const deleteProjectObservation = `-- name: DeleteProjectObservation :exec
+delete from observation
+where project_id = ?
+  and id = ?
+`
+
+type DeleteProjectObservationParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) DeleteProjectObservation(ctx context.Context, arg DeleteProjectObservationParams) error {
+	_, err := q.db.ExecContext(ctx, deleteProjectObservation, arg.ProjectID, arg.ID)
+	return err
+}
+
+const deleteTaxonWatch = `-- name: DeleteTaxonWatch :execrows
+delete from taxon_watch
+where guild_id = ?
+  and discord_user_id = ?
+  and taxon_id = ?
+`
+
+type DeleteTaxonWatchParams struct {
+	GuildID       string `json:"guild_id"`
+	DiscordUserID string `json:"discord_user_id"`
+	TaxonID       int64  `json:"taxon_id"`
+}
+
+func (q *Queries) DeleteTaxonWatch(ctx context.Context, arg DeleteTaxonWatchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTaxonWatch, arg.GuildID, arg.DiscordUserID, arg.TaxonID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWatchNotifications = `-- name: DeleteWatchNotifications :exec
+delete from watch_notification
+where guild_id = ?
+  and discord_user_id = ?
+`
+
+type DeleteWatchNotificationsParams struct {
+	GuildID       string `json:"guild_id"`
+	DiscordUserID string `json:"discord_user_id"`
+}
+
+func (q *Queries) DeleteWatchNotifications(ctx context.Context, arg DeleteWatchNotificationsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWatchNotifications, arg.GuildID, arg.DiscordUserID)
+	return err
+}
+
+const findAnnouncementMark = `-- name: FindAnnouncementMark :one
+select
+  channel_id, feed, project_id, last_id, created_at, updated_at
+from
+  announcement_mark
+where
+  channel_id = ?
+  and feed = ?
+`
+
+type FindAnnouncementMarkParams struct {
 	ChannelID string `json:"channel_id"`
-	MessageID string `json:"message_id"`
-	GuildID   string `json:"guild_id"`
+	Feed      string `json:"feed"`
 }
 
-func (q *Queries) FindIsMessageFeatured(ctx context.Context, arg FindIsMessageFeaturedParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, findIsMessageFeatured, arg.ChannelID, arg.MessageID, arg.GuildID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+func (q *Queries) FindAnnouncementMark(ctx context.Context, arg FindAnnouncementMarkParams) (AnnouncementMark, error) {
+	row := q.db.QueryRowContext(ctx, findAnnouncementMark, arg.ChannelID, arg.Feed)
+	var i AnnouncementMark
+	err := row.Scan(
+		&i.ChannelID,
+		&i.Feed,
+		&i.ProjectID,
+		&i.LastID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findBallot = `-- name: FindBallot :one
+select
+  id, channel_id, period, message_id, candidates, closed_at, created_at, updated_at
+from
+  ballot
+where
+  channel_id = ?
+  and period = ?
+`
+
+type FindBallotParams struct {
+	ChannelID string `json:"channel_id"`
+	Period    string `json:"period"`
+}
+
+func (q *Queries) FindBallot(ctx context.Context, arg FindBallotParams) (Ballot, error) {
+	row := q.db.QueryRowContext(ctx, findBallot, arg.ChannelID, arg.Period)
+	var i Ballot
+	err := row.Scan(
+		&i.ID,
+		&i.ChannelID,
+		&i.Period,
+		&i.MessageID,
+		&i.Candidates,
+		&i.ClosedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findBallotByID = `-- name: FindBallotByID :one
+select
+  id, channel_id, period, message_id, candidates, closed_at, created_at, updated_at
+from
+  ballot
+where
+  id = ?
+`
+
+func (q *Queries) FindBallotByID(ctx context.Context, id int64) (Ballot, error) {
+	row := q.db.QueryRowContext(ctx, findBallotByID, id)
+	var i Ballot
+	err := row.Scan(
+		&i.ID,
+		&i.ChannelID,
+		&i.Period,
+		&i.MessageID,
+		&i.Candidates,
+		&i.ClosedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findComponentState = `-- name: FindComponentState :one
+select
+  id, module, data, created_at
+from
+  component_state
+where
+  id = ?
+`
+
+func (q *Queries) FindComponentState(ctx context.Context, id string) (ComponentState, error) {
+	row := q.db.QueryRowContext(ctx, findComponentState, id)
+	var i ComponentState
+	err := row.Scan(
+		&i.ID,
+		&i.Module,
+		&i.Data,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findCooldown = `-- name: FindCooldown :one
+select
+  "key", expires_at, created_at, updated_at
+from
+  cooldown
+where
+  key = ?
+`
+
+func (q *Queries) FindCooldown(ctx context.Context, key string) (Cooldown, error) {
+	row := q.db.QueryRowContext(ctx, findCooldown, key)
+	var i Cooldown
+	err := row.Scan(
+		&i.Key,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findDisplayedObservers = `-- name: FindDisplayedObservers :many
+select
+  channel_id, project_id, feed, user_id, created_at, updated_at
+from
+  displayed_observer
+where (channel_id = ?1
+  or ?1 = '')
+and (project_id = ?2
+  or ?2 = 0)
+and (feed = ?3
+  or ?3 = '')
+order by
+  channel_id,
+  project_id,
+  feed,
+  created_at
+`
+
+type FindDisplayedObserversParams struct {
+	ChannelID string `json:"channel_id"`
+	ProjectID int64  `json:"project_id"`
+	Feed      string `json:"feed"`
+}
+
+func (q *Queries) FindDisplayedObservers(ctx context.Context, arg FindDisplayedObserversParams) ([]DisplayedObserver, error) {
+	rows, err := q.db.QueryContext(ctx, findDisplayedObservers, arg.ChannelID, arg.ProjectID, arg.Feed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DisplayedObserver
+	for rows.Next() {
+		var i DisplayedObserver
+		if err := rows.Scan(
+			&i.ChannelID,
+			&i.ProjectID,
+			&i.Feed,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findDueOutboxMessages = `-- name: FindDueOutboxMessages :many
+select
+  id, module, kind, channel_id, dedupe_key, payload, meta, status, attempts, next_attempt_at, last_error, message_id, created_at, updated_at
+from
+  outbox_message
+where
+  status = 'pending'
+  and next_attempt_at <= ?1
+order by
+  id
+limit ?2
+`
+
+type FindDueOutboxMessagesParams struct {
+	Now   time.Time `json:"now"`
+	Limit int64     `json:"limit"`
+}
+
+func (q *Queries) FindDueOutboxMessages(ctx context.Context, arg FindDueOutboxMessagesParams) ([]OutboxMessage, error) {
+	rows, err := q.db.QueryContext(ctx, findDueOutboxMessages, arg.Now, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OutboxMessage
+	for rows.Next() {
+		var i OutboxMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Module,
+			&i.Kind,
+			&i.ChannelID,
+			&i.DedupeKey,
+			&i.Payload,
+			&i.Meta,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastError,
+			&i.MessageID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findInatAccount = `-- name: FindInatAccount :one
+select
+  discord_user_id, inat_user_id, inat_login, created_at, updated_at
+from
+  inat_account
+where
+  discord_user_id = ?
+`
+
+func (q *Queries) FindInatAccount(ctx context.Context, discordUserID string) (InatAccount, error) {
+	row := q.db.QueryRowContext(ctx, findInatAccount, discordUserID)
+	var i InatAccount
+	err := row.Scan(
+		&i.DiscordUserID,
+		&i.InatUserID,
+		&i.InatLogin,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findInatAccountByInatUser = `-- name: FindInatAccountByInatUser :one
+select
+  discord_user_id, inat_user_id, inat_login, created_at, updated_at
+from
+  inat_account
+where
+  inat_user_id = ?
+`
+
+func (q *Queries) FindInatAccountByInatUser(ctx context.Context, inatUserID int64) (InatAccount, error) {
+	row := q.db.QueryRowContext(ctx, findInatAccountByInatUser, inatUserID)
+	var i InatAccount
+	err := row.Scan(
+		&i.DiscordUserID,
+		&i.InatUserID,
+		&i.InatLogin,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findInatAccountVerification = `-- name: FindInatAccountVerification :one
+select
+  discord_user_id, inat_login, code, created_at
+from
+  inat_account_verification
+where
+  discord_user_id = ?
+`
+
+func (q *Queries) FindInatAccountVerification(ctx context.Context, discordUserID string) (InatAccountVerification, error) {
+	row := q.db.QueryRowContext(ctx, findInatAccountVerification, discordUserID)
+	var i InatAccountVerification
+	err := row.Scan(
+		&i.DiscordUserID,
+		&i.InatLogin,
+		&i.Code,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findIsMessageFeatured = `-- name: FindIsMessageFeatured :one
+select
+  exists (
+    select
+      1
+    from
+      featured_message
+    where
+      channel_id = ?
+      and message_id = ?
+      and guild_id = ?
+    limit 1)
+`
+
+type FindIsMessageFeaturedParams struct {
+	ChannelID string `json:"channel_id"`
+	MessageID string `json:"message_id"`
+	GuildID   string `json:"guild_id"`
+}
+
+func (q *Queries) FindIsMessageFeatured(ctx context.Context, arg FindIsMessageFeaturedParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, findIsMessageFeatured, arg.ChannelID, arg.MessageID, arg.GuildID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const findJobRuns = `-- name: FindJobRuns :many
+select
+  id, job_name, started_at, finished_at, status, error, catch_up
+from
+  job_run
+where
+  job_name = ?
+order by
+  id desc
+limit ?
+`
+
+type FindJobRunsParams struct {
+	JobName string `json:"job_name"`
+	Limit   int64  `json:"limit"`
+}
+
+func (q *Queries) FindJobRuns(ctx context.Context, arg FindJobRunsParams) ([]JobRun, error) {
+	rows, err := q.db.QueryContext(ctx, findJobRuns, arg.JobName, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []JobRun
+	for rows.Next() {
+		var i JobRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobName,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.Status,
+			&i.Error,
+			&i.CatchUp,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findLeaderboardSnapshot = `-- name: FindLeaderboardSnapshot :one
+select
+  channel_id, project_id, period, period_start, data, created_at, updated_at
+from
+  leaderboard_snapshot
+where
+  channel_id = ?
+  and project_id = ?
+  and period = ?
+  and period_start = ?
+`
+
+type FindLeaderboardSnapshotParams struct {
+	ChannelID   string `json:"channel_id"`
+	ProjectID   int64  `json:"project_id"`
+	Period      string `json:"period"`
+	PeriodStart string `json:"period_start"`
+}
+
+func (q *Queries) FindLeaderboardSnapshot(ctx context.Context, arg FindLeaderboardSnapshotParams) (LeaderboardSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, findLeaderboardSnapshot,
+		arg.ChannelID,
+		arg.ProjectID,
+		arg.Period,
+		arg.PeriodStart,
+	)
+	var i LeaderboardSnapshot
+	err := row.Scan(
+		&i.ChannelID,
+		&i.ProjectID,
+		&i.Period,
+		&i.PeriodStart,
+		&i.Data,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findLookupReply = `-- name: FindLookupReply :one
+select
+  message_id, channel_id, reply_id, author_id, content, created_at
+from
+  lookup_reply
+where
+  message_id = ?
+`
+
+func (q *Queries) FindLookupReply(ctx context.Context, messageID string) (LookupReply, error) {
+	row := q.db.QueryRowContext(ctx, findLookupReply, messageID)
+	var i LookupReply
+	err := row.Scan(
+		&i.MessageID,
+		&i.ChannelID,
+		&i.ReplyID,
+		&i.AuthorID,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findLookupReplyByReply = `-- name: FindLookupReplyByReply :one
+select
+  message_id, channel_id, reply_id, author_id, content, created_at
+from
+  lookup_reply
+where
+  reply_id = ?
+`
+
+func (q *Queries) FindLookupReplyByReply(ctx context.Context, replyID string) (LookupReply, error) {
+	row := q.db.QueryRowContext(ctx, findLookupReplyByReply, replyID)
+	var i LookupReply
+	err := row.Scan(
+		&i.MessageID,
+		&i.ChannelID,
+		&i.ReplyID,
+		&i.AuthorID,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findModuleConfiguration = `-- name: FindModuleConfiguration :one
+select
+  module, "key", data
+from
+  module_configuration
+where
+  module = ?
+  and key = ?
+`
+
+type FindModuleConfigurationParams struct {
+	Module string `json:"module"`
+	Key    string `json:"key"`
+}
+
+func (q *Queries) FindModuleConfiguration(ctx context.Context, arg FindModuleConfigurationParams) (ModuleConfiguration, error) {
+	row := q.db.QueryRowContext(ctx, findModuleConfiguration, arg.Module, arg.Key)
+	var i ModuleConfiguration
+	err := row.Scan(&i.Module, &i.Key, &i.Data)
+	return i, err
+}
+
+const findModuleConfigurations = `-- name: FindModuleConfigurations :many
+select
+  module, "key", data
+from
+  module_configuration
+where
+  module = ?
+`
+
+func (q *Queries) FindModuleConfigurations(ctx context.Context, module string) ([]ModuleConfiguration, error) {
+	rows, err := q.db.QueryContext(ctx, findModuleConfigurations, module)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ModuleConfiguration
+	for rows.Next() {
+		var i ModuleConfiguration
+		if err := rows.Scan(&i.Module, &i.Key, &i.Data); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findObservationSync = `-- name: FindObservationSync :one
+select
+  project_id, newest_id, oldest_id, backfill_complete, refreshed_at, created_at, updated_at
+from
+  observation_sync
+where
+  project_id = ?
+`
+
+func (q *Queries) FindObservationSync(ctx context.Context, projectID int64) (ObservationSync, error) {
+	row := q.db.QueryRowContext(ctx, findObservationSync, projectID)
+	var i ObservationSync
+	err := row.Scan(
+		&i.ProjectID,
+		&i.NewestID,
+		&i.OldestID,
+		&i.BackfillComplete,
+		&i.RefreshedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findOpenBallot = `-- name: FindOpenBallot :one
+select
+  id, channel_id, period, message_id, candidates, closed_at, created_at, updated_at
+from
+  ballot
+where
+  channel_id = ?
+  and closed_at is null
+order by
+  id desc
+limit 1
+`
+
+func (q *Queries) FindOpenBallot(ctx context.Context, channelID string) (Ballot, error) {
+	row := q.db.QueryRowContext(ctx, findOpenBallot, channelID)
+	var i Ballot
+	err := row.Scan(
+		&i.ID,
+		&i.ChannelID,
+		&i.Period,
+		&i.MessageID,
+		&i.Candidates,
+		&i.ClosedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findProjectMilestone = `-- name: FindProjectMilestone :one
+select
+  channel_id, project_id, observation_count, species_count, observer_count, last_observation_id, created_at, updated_at
+from
+  project_milestone
+where
+  channel_id = ?
+  and project_id = ?
+`
+
+type FindProjectMilestoneParams struct {
+	ChannelID string `json:"channel_id"`
+	ProjectID int64  `json:"project_id"`
+}
+
+func (q *Queries) FindProjectMilestone(ctx context.Context, arg FindProjectMilestoneParams) (ProjectMilestone, error) {
+	row := q.db.QueryRowContext(ctx, findProjectMilestone, arg.ChannelID, arg.ProjectID)
+	var i ProjectMilestone
+	err := row.Scan(
+		&i.ChannelID,
+		&i.ProjectID,
+		&i.ObservationCount,
+		&i.SpeciesCount,
+		&i.ObserverCount,
+		&i.LastObservationID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findProjectObservation = `-- name: FindProjectObservation :one
+select
+  id, project_id, user_id, taxon_id, quality_grade, faves_count, photo_count, observed_on, data, created_at, updated_at, user_login, iconic_taxon, taxon_ancestry, place_ids
+from
+  observation
+where
+  project_id = ?
+  and id = ?
+`
+
+type FindProjectObservationParams struct {
+	ProjectID int64 `json:"project_id"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) FindProjectObservation(ctx context.Context, arg FindProjectObservationParams) (Observation, error) {
+	row := q.db.QueryRowContext(ctx, findProjectObservation, arg.ProjectID, arg.ID)
+	var i Observation
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.UserID,
+		&i.TaxonID,
+		&i.QualityGrade,
+		&i.FavesCount,
+		&i.PhotoCount,
+		&i.ObservedOn,
+		&i.Data,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserLogin,
+		&i.IconicTaxon,
+		&i.TaxonAncestry,
+		&i.PlaceIds,
+	)
+	return i, err
+}
+
+const findScheduledJob = `-- name: FindScheduledJob :one
+select
+  name, module, schedule, timezone, catch_up, last_run_at, last_status, last_error, created_at, updated_at
+from
+  scheduled_job
+where
+  name = ?
+`
+
+func (q *Queries) FindScheduledJob(ctx context.Context, name string) (ScheduledJob, error) {
+	row := q.db.QueryRowContext(ctx, findScheduledJob, name)
+	var i ScheduledJob
+	err := row.Scan(
+		&i.Name,
+		&i.Module,
+		&i.Schedule,
+		&i.Timezone,
+		&i.CatchUp,
+		&i.LastRunAt,
+		&i.LastStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findScheduledJobs = `-- name: FindScheduledJobs :many
+select
+  name, module, schedule, timezone, catch_up, last_run_at, last_status, last_error, created_at, updated_at
+from
+  scheduled_job
+order by
+  name
+`
+
+func (q *Queries) FindScheduledJobs(ctx context.Context) ([]ScheduledJob, error) {
+	rows, err := q.db.QueryContext(ctx, findScheduledJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ScheduledJob
+	for rows.Next() {
+		var i ScheduledJob
+		if err := rows.Scan(
+			&i.Name,
+			&i.Module,
+			&i.Schedule,
+			&i.Timezone,
+			&i.CatchUp,
+			&i.LastRunAt,
+			&i.LastStatus,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findUnseenObservationCandidates = `-- name: FindUnseenObservationCandidates :many
+with filter as (
+  select
+    cast(?4 as text) as taxon_ids,
+    cast(?5 as text) as iconic_taxa,
+    cast(?6 as text) as quality_grades,
+    cast(?7 as text) as place_ids,
+    cast(?8 as text) as excluded_users,
+    cast(?9 as text) as observed_after,
+    cast(?10 as text) as observed_before,
+    cast(?11 as text) as photos,
+    cast(?12 as integer) as min_faves,
+    cast(?13 as text) as user_login,
+    cast(?14 as integer) as taxon_id
+),
+taxon_count as (
+  select
+    taxon_id,
+    count(*) as taxon_count
+  from
+    observation
+  where
+    project_id = ?3
+  group by
+    taxon_id
+)
+select
+  o.id,
+  o.user_id,
+  o.user_login,
+  o.taxon_id,
+  o.iconic_taxon,
+  o.taxon_ancestry,
+  o.place_ids,
+  o.quality_grade,
+  o.faves_count,
+  o.photo_count,
+  o.observed_on,
+  cast(coalesce(t.taxon_count, 0) as integer) as taxon_count
+from
+  observation o
+  cross join filter
+  left join taxon_count t on t.taxon_id = o.taxon_id
+  left join seen_observation s on s.id = o.id
+    and s.project_id = o.project_id
+    and s.channel_id = ?1
+    and s.feed = ?2
+where
+  o.project_id = ?3
+  and s.id is null
+  and (filter.taxon_ids = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.taxon_ids) f
+      where
+        ',' || o.taxon_ancestry || ',' like '%,' || f.value || ',%'))
+  and (filter.iconic_taxa = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.iconic_taxa) f
+      where
+        lower(f.value) = lower(o.iconic_taxon)))
+  and (filter.quality_grades = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.quality_grades) f
+      where
+        f.value = o.quality_grade))
+  and (filter.place_ids = '[]'
+    or exists (
+      select
+        1
+      from
+        json_each(filter.place_ids) f
+      where
+        ',' || o.place_ids || ',' like '%,' || f.value || ',%'))
+  and not exists (
+    select
+      1
+    from
+      json_each(filter.excluded_users) f
+    where
+      lower(f.value) = lower(o.user_login)
+      or f.value = cast(o.user_id as text))
+  -- observed_on is stored as YYYY-MM-DD, so the dates compare as strings
+  and (filter.observed_after = ''
+    or (o.observed_on != ''
+      and o.observed_on >= filter.observed_after))
+  and (filter.observed_before = ''
+    or (o.observed_on != ''
+      and o.observed_on <= filter.observed_before))
+  and (filter.photos = 'any'
+    or (filter.photos = 'true'
+      and o.photo_count > 0)
+    or (filter.photos = 'false'
+      and o.photo_count <= 0))
+  and o.faves_count >= filter.min_faves
+  and (filter.user_login = ''
+    or lower(o.user_login) = lower(filter.user_login))
+  and (filter.taxon_id = 0
+    or ',' || o.taxon_ancestry || ',' like '%,' || filter.taxon_id || ',%')
+`
+
+type FindUnseenObservationCandidatesParams struct {
+	ChannelID      string `json:"channel_id"`
+	Feed           string `json:"feed"`
+	ProjectID      int64  `json:"project_id"`
+	TaxonIds       string `json:"taxon_ids"`
+	IconicTaxa     string `json:"iconic_taxa"`
+	QualityGrades  string `json:"quality_grades"`
+	PlaceIds       string `json:"place_ids"`
+	ExcludedUsers  string `json:"excluded_users"`
+	ObservedAfter  string `json:"observed_after"`
+	ObservedBefore string `json:"observed_before"`
+	Photos         string `json:"photos"`
+	MinFaves       int64  `json:"min_faves"`
+	UserLogin      string `json:"user_login"`
+	TaxonID        int64  `json:"taxon_id"`
+}
+
+type FindUnseenObservationCandidatesRow struct {
+	ID            int64  `json:"id"`
+	UserID        int64  `json:"user_id"`
+	UserLogin     string `json:"user_login"`
+	TaxonID       int64  `json:"taxon_id"`
+	IconicTaxon   string `json:"iconic_taxon"`
+	TaxonAncestry string `json:"taxon_ancestry"`
+	PlaceIds      string `json:"place_ids"`
+	QualityGrade  string `json:"quality_grade"`
+	FavesCount    int64  `json:"faves_count"`
+	PhotoCount    int64  `json:"photo_count"`
+	ObservedOn    string `json:"observed_on"`
+	TaxonCount    int64  `json:"taxon_count"`
+}
+
+// list filters are json arrays, an empty array matches everything
+func (q *Queries) FindUnseenObservationCandidates(ctx context.Context, arg FindUnseenObservationCandidatesParams) ([]FindUnseenObservationCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, findUnseenObservationCandidates,
+		arg.ChannelID,
+		arg.Feed,
+		arg.ProjectID,
+		arg.TaxonIds,
+		arg.IconicTaxa,
+		arg.QualityGrades,
+		arg.PlaceIds,
+		arg.ExcludedUsers,
+		arg.ObservedAfter,
+		arg.ObservedBefore,
+		arg.Photos,
+		arg.MinFaves,
+		arg.UserLogin,
+		arg.TaxonID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindUnseenObservationCandidatesRow
+	for rows.Next() {
+		var i FindUnseenObservationCandidatesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.UserLogin,
+			&i.TaxonID,
+			&i.IconicTaxon,
+			&i.TaxonAncestry,
+			&i.PlaceIds,
+			&i.QualityGrade,
+			&i.FavesCount,
+			&i.PhotoCount,
+			&i.ObservedOn,
+			&i.TaxonCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findWatchMark = `-- name: FindWatchMark :one
+select
+  guild_id, project_id, last_id, created_at, updated_at
+from
+  watch_mark
+where
+  guild_id = ?
+`
+
+func (q *Queries) FindWatchMark(ctx context.Context, guildID string) (WatchMark, error) {
+	row := q.db.QueryRowContext(ctx, findWatchMark, guildID)
+	var i WatchMark
+	err := row.Scan(
+		&i.GuildID,
+		&i.ProjectID,
+		&i.LastID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const findWatchSettings = `-- name: FindWatchSettings :one
+select
+  discord_user_id, quiet_hours, timezone, last_notified_at, created_at, updated_at
+from
+  watch_settings
+where
+  discord_user_id = ?
+`
+
+func (q *Queries) FindWatchSettings(ctx context.Context, discordUserID string) (WatchSetting, error) {
+	row := q.db.QueryRowContext(ctx, findWatchSettings, discordUserID)
+	var i WatchSetting
+	err := row.Scan(
+		&i.DiscordUserID,
+		&i.QuietHours,
+		&i.Timezone,
+		&i.LastNotifiedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const findModuleConfiguration = `-- name: FindModuleConfiguration :one
+const listGuildTaxonWatches = `-- name: ListGuildTaxonWatches :many
 select
-  module, "key", data
+  guild_id, discord_user_id, taxon_id, taxon_name, created_at
 from
-  module_configuration
+  taxon_watch
 where
-  module = ?
-  and key = ?
+  guild_id = ?
 `
 
-type FindModuleConfigurationParams struct {
-	Module string `json:"module"`
-	Key    string `json:"key"`
-}
-
-func (q *Queries) FindModuleConfiguration(ctx context.Context, arg FindModuleConfigurationParams) (ModuleConfiguration, error) {
-	row := q.db.QueryRowContext(ctx, findModuleConfiguration, arg.Module, arg.Key)
-	var i ModuleConfiguration
-	err := row.Scan(&i.Module, &i.Key, &i.Data)
-	return i, err
+func (q *Queries) ListGuildTaxonWatches(ctx context.Context, guildID string) ([]TaxonWatch, error) {
+	rows, err := q.db.QueryContext(ctx, listGuildTaxonWatches, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxonWatch
+	for rows.Next() {
+		var i TaxonWatch
+		if err := rows.Scan(
+			&i.GuildID,
+			&i.DiscordUserID,
+			&i.TaxonID,
+			&i.TaxonName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const findModuleConfigurations = `-- name: FindModuleConfigurations :many
+const listInatAccounts = `-- name: ListInatAccounts :many
 select
-  module, "key", data
+  discord_user_id, inat_user_id, inat_login, created_at, updated_at
 from
-  module_configuration
-where
-  module = ?
+  inat_account
+order by
+  discord_user_id
 `
 
-func (q *Queries) FindModuleConfigurations(ctx context.Context, module string) ([]ModuleConfiguration, error) {
-	rows, err := q.db.QueryContext(ctx, findModuleConfigurations, module)
+func (q *Queries) ListInatAccounts(ctx context.Context) ([]InatAccount, error) {
+	rows, err := q.db.QueryContext(ctx, listInatAccounts)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ModuleConfiguration
+	var items []InatAccount
 	for rows.Next() {
-		var i ModuleConfiguration
-		if err := rows.Scan(&i.Module, &i.Key, &i.Data); err != nil {
+		var i InatAccount
+		if err := rows.Scan(
+			&i.DiscordUserID,
+			&i.InatUserID,
+			&i.InatLogin,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -158,37 +1514,27 @@ func (q *Queries) FindModuleConfigurations(ctx context.Context, module string) (
 	return items, nil
 }
 
-const findObservations = `-- name: FindObservations :many
+const listPostedObservations = `-- name: ListPostedObservations :many
 select
-  id, channel_id, project_id, created_at, updated_at
+  id, channel_id, project_id, feed, created_at, updated_at
 from
   seen_observation
 where
-  project_id = ?1
-  and channel_id = ?2
-  and id in (/*SLICE:id*/?)
+  channel_id = ?1
+  and created_at >= ?2
+  and created_at < ?3
+order by
+  id
 `
 
-type FindObservationsParams struct {
-	ProjectID int64   `json:"project_id"`
-	ChannelID string  `json:"channel_id"`
-	ID        []int64 `json:"id"`
+type ListPostedObservationsParams struct {
+	ChannelID string    `json:"channel_id"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
 }
 
-func (q *Queries) FindObservations(ctx context.Context, arg FindObservationsParams) ([]SeenObservation, error) {
-	query := findObservations
-	var queryParams []interface{}
-	queryParams = append(queryParams, arg.ProjectID)
-	queryParams = append(queryParams, arg.ChannelID)
-	if len(arg.ID) > 0 {
-		for _, v := range arg.ID {
-			queryParams = append(queryParams, v)
-		}
-		query = strings.Replace(query, "/*SLICE:id*/?", strings.Repeat(",?", len(arg.ID))[1:], 1)
-	} else {
-		query = strings.Replace(query, "/*SLICE:id*/?", "NULL", 1)
-	}
-	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+func (q *Queries) ListPostedObservations(ctx context.Context, arg ListPostedObservationsParams) ([]SeenObservation, error) {
+	rows, err := q.db.QueryContext(ctx, listPostedObservations, arg.ChannelID, arg.Start, arg.End)
 	if err != nil {
 		return nil, err
 	}
@@ -200,6 +1546,7 @@ func (q *Queries) FindObservations(ctx context.Context, arg FindObservationsPara
 			&i.ID,
 			&i.ChannelID,
 			&i.ProjectID,
+			&i.Feed,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -216,6 +1563,157 @@ func (q *Queries) FindObservations(ctx context.Context, arg FindObservationsPara
 	return items, nil
 }
 
+const listUserTaxonWatches = `-- name: ListUserTaxonWatches :many
+select
+  guild_id, discord_user_id, taxon_id, taxon_name, created_at
+from
+  taxon_watch
+where
+  guild_id = ?
+  and discord_user_id = ?
+order by
+  taxon_name
+`
+
+type ListUserTaxonWatchesParams struct {
+	GuildID       string `json:"guild_id"`
+	DiscordUserID string `json:"discord_user_id"`
+}
+
+func (q *Queries) ListUserTaxonWatches(ctx context.Context, arg ListUserTaxonWatchesParams) ([]TaxonWatch, error) {
+	rows, err := q.db.QueryContext(ctx, listUserTaxonWatches, arg.GuildID, arg.DiscordUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TaxonWatch
+	for rows.Next() {
+		var i TaxonWatch
+		if err := rows.Scan(
+			&i.GuildID,
+			&i.DiscordUserID,
+			&i.TaxonID,
+			&i.TaxonName,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWatchNotifications = `-- name: ListWatchNotifications :many
+select
+  guild_id, discord_user_id, observation_id, data, created_at
+from
+  watch_notification
+where
+  guild_id = ?
+order by
+  discord_user_id,
+  observation_id
+`
+
+func (q *Queries) ListWatchNotifications(ctx context.Context, guildID string) ([]WatchNotification, error) {
+	rows, err := q.db.QueryContext(ctx, listWatchNotifications, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WatchNotification
+	for rows.Next() {
+		var i WatchNotification
+		if err := rows.Scan(
+			&i.GuildID,
+			&i.DiscordUserID,
+			&i.ObservationID,
+			&i.Data,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const saveAnnouncementMark = `-- name: SaveAnnouncementMark :exec
+insert into announcement_mark (channel_id, feed, project_id, last_id)
+  values (?, ?, ?, ?)
+on conflict (channel_id, feed)
+  do update set
+    project_id = excluded.project_id, last_id = excluded.last_id, updated_at = current_timestamp
+`
+
+type SaveAnnouncementMarkParams struct {
+	ChannelID string `json:"channel_id"`
+	Feed      string `json:"feed"`
+	ProjectID int64  `json:"project_id"`
+	LastID    int64  `json:"last_id"`
+}
+
+func (q *Queries) SaveAnnouncementMark(ctx context.Context, arg SaveAnnouncementMarkParams) error {
+	_, err := q.db.ExecContext(ctx, saveAnnouncementMark,
+		arg.ChannelID,
+		arg.Feed,
+		arg.ProjectID,
+		arg.LastID,
+	)
+	return err
+}
+
+const saveBallotMessage = `-- name: SaveBallotMessage :exec
+update
+  ballot
+set
+  message_id = ?,
+  updated_at = current_timestamp
+where
+  id = ?
+`
+
+type SaveBallotMessageParams struct {
+	MessageID string `json:"message_id"`
+	ID        int64  `json:"id"`
+}
+
+func (q *Queries) SaveBallotMessage(ctx context.Context, arg SaveBallotMessageParams) error {
+	_, err := q.db.ExecContext(ctx, saveBallotMessage, arg.MessageID, arg.ID)
+	return err
+}
+
+const saveBallotVote = `-- name: SaveBallotVote :exec
+insert into ballot_vote (ballot_id, discord_user_id, observation_id)
+  values (?, ?, ?)
+on conflict (ballot_id, discord_user_id)
+  do update set
+    observation_id = excluded.observation_id, updated_at = current_timestamp
+`
+
+type SaveBallotVoteParams struct {
+	BallotID      int64  `json:"ballot_id"`
+	DiscordUserID string `json:"discord_user_id"`
+	ObservationID int64  `json:"observation_id"`
+}
+
+func (q *Queries) SaveBallotVote(ctx context.Context, arg SaveBallotVoteParams) error {
+	_, err := q.db.ExecContext(ctx, saveBallotVote, arg.BallotID, arg.DiscordUserID, arg.ObservationID)
+	return err
+}
+
 const saveFeaturedMessage = `-- name: SaveFeaturedMessage :one
 insert
   or ignore into featured_message (message_id, channel_id, guild_id)
@@ -243,6 +1741,297 @@ func (q *Queries) SaveFeaturedMessage(ctx context.Context, arg SaveFeaturedMessa
 	return i, err
 }
 
+const saveInatAccount = `-- name: SaveInatAccount :exec
+insert into inat_account (discord_user_id, inat_user_id, inat_login)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    inat_user_id = excluded.inat_user_id, inat_login = excluded.inat_login, updated_at = current_timestamp
+`
+
+type SaveInatAccountParams struct {
+	DiscordUserID string `json:"discord_user_id"`
+	InatUserID    int64  `json:"inat_user_id"`
+	InatLogin     string `json:"inat_login"`
+}
+
+func (q *Queries) SaveInatAccount(ctx context.Context, arg SaveInatAccountParams) error {
+	_, err := q.db.ExecContext(ctx, saveInatAccount, arg.DiscordUserID, arg.InatUserID, arg.InatLogin)
+	return err
+}
+
+const saveInatAccountVerification = `-- name: SaveInatAccountVerification :exec
+insert into inat_account_verification (discord_user_id, inat_login, code)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    inat_login = excluded.inat_login, code = excluded.code, created_at = current_timestamp
+`
+
+type SaveInatAccountVerificationParams struct {
+	DiscordUserID string `json:"discord_user_id"`
+	InatLogin     string `json:"inat_login"`
+	Code          string `json:"code"`
+}
+
+func (q *Queries) SaveInatAccountVerification(ctx context.Context, arg SaveInatAccountVerificationParams) error {
+	_, err := q.db.ExecContext(ctx, saveInatAccountVerification, arg.DiscordUserID, arg.InatLogin, arg.Code)
+	return err
+}
+
+const saveLeaderboardSnapshot = `-- name: SaveLeaderboardSnapshot :exec
+insert into leaderboard_snapshot (channel_id, project_id, period, period_start, data)
+  values (?, ?, ?, ?, ?)
+on conflict (channel_id, project_id, period, period_start)
+  do update set
+    data = excluded.data, updated_at = current_timestamp
+`
+
+type SaveLeaderboardSnapshotParams struct {
+	ChannelID   string `json:"channel_id"`
+	ProjectID   int64  `json:"project_id"`
+	Period      string `json:"period"`
+	PeriodStart string `json:"period_start"`
+	Data        string `json:"data"`
+}
+
+func (q *Queries) SaveLeaderboardSnapshot(ctx context.Context, arg SaveLeaderboardSnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, saveLeaderboardSnapshot,
+		arg.ChannelID,
+		arg.ProjectID,
+		arg.Period,
+		arg.PeriodStart,
+		arg.Data,
+	)
+	return err
+}
+
+const saveLookupReply = `-- name: SaveLookupReply :exec
+insert into lookup_reply (message_id, channel_id, reply_id, author_id, content)
+  values (?, ?, ?, ?, ?)
+on conflict (message_id)
+  do update set
+    reply_id = excluded.reply_id, content = excluded.content
+`
+
+type SaveLookupReplyParams struct {
+	MessageID string `json:"message_id"`
+	ChannelID string `json:"channel_id"`
+	ReplyID   string `json:"reply_id"`
+	AuthorID  string `json:"author_id"`
+	Content   string `json:"content"`
+}
+
+func (q *Queries) SaveLookupReply(ctx context.Context, arg SaveLookupReplyParams) error {
+	_, err := q.db.ExecContext(ctx, saveLookupReply,
+		arg.MessageID,
+		arg.ChannelID,
+		arg.ReplyID,
+		arg.AuthorID,
+		arg.Content,
+	)
+	return err
+}
+
+const saveObservationSync = `-- name: SaveObservationSync :one
+insert into observation_sync (project_id, newest_id, oldest_id,
+  backfill_complete, refreshed_at)
+  values (?, ?, ?, ?, ?)
+on conflict (project_id)
+  do update set
+    newest_id = excluded.newest_id, oldest_id = excluded.oldest_id,
+      backfill_complete = excluded.backfill_complete, refreshed_at =
+      excluded.refreshed_at, updated_at = current_timestamp
+  returning
+    project_id, newest_id, oldest_id, backfill_complete, refreshed_at, created_at, updated_at
+`
+
+type SaveObservationSyncParams struct {
+	ProjectID        int64     `json:"project_id"`
+	NewestID         int64     `json:"newest_id"`
+	OldestID         int64     `json:"oldest_id"`
+	BackfillComplete bool      `json:"backfill_complete"`
+	RefreshedAt      time.Time `json:"refreshed_at"`
+}
+
+func (q *Queries) SaveObservationSync(ctx context.Context, arg SaveObservationSyncParams) (ObservationSync, error) {
+	row := q.db.QueryRowContext(ctx, saveObservationSync,
+		arg.ProjectID,
+		arg.NewestID,
+		arg.OldestID,
+		arg.BackfillComplete,
+		arg.RefreshedAt,
+	)
+	var i ObservationSync
+	err := row.Scan(
+		&i.ProjectID,
+		&i.NewestID,
+		&i.OldestID,
+		&i.BackfillComplete,
+		&i.RefreshedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const saveProjectMilestone = `-- name: SaveProjectMilestone :exec
+insert into project_milestone (channel_id, project_id, observation_count, species_count,
+  observer_count, last_observation_id)
+  values (?, ?, ?, ?, ?, ?)
+on conflict (channel_id, project_id)
+  do update set
+    observation_count = excluded.observation_count, species_count =
+      excluded.species_count, observer_count = excluded.observer_count,
+      last_observation_id = excluded.last_observation_id, updated_at = current_timestamp
+`
+
+type SaveProjectMilestoneParams struct {
+	ChannelID         string `json:"channel_id"`
+	ProjectID         int64  `json:"project_id"`
+	ObservationCount  int64  `json:"observation_count"`
+	SpeciesCount      int64  `json:"species_count"`
+	ObserverCount     int64  `json:"observer_count"`
+	LastObservationID int64  `json:"last_observation_id"`
+}
+
+func (q *Queries) SaveProjectMilestone(ctx context.Context, arg SaveProjectMilestoneParams) error {
+	_, err := q.db.ExecContext(ctx, saveProjectMilestone,
+		arg.ChannelID,
+		arg.ProjectID,
+		arg.ObservationCount,
+		arg.SpeciesCount,
+		arg.ObserverCount,
+		arg.LastObservationID,
+	)
+	return err
+}
+
+const saveScheduledJob = `-- name: SaveScheduledJob :one
+insert into scheduled_job (name, module, schedule, timezone, catch_up)
+  values (?, ?, ?, ?, ?)
+on conflict (name)
+  do update set
+    module = excluded.module, schedule = excluded.schedule, timezone =
+      excluded.timezone, catch_up = excluded.catch_up, updated_at = current_timestamp
+  returning
+    name, module, schedule, timezone, catch_up, last_run_at, last_status, last_error, created_at, updated_at
+`
+
+type SaveScheduledJobParams struct {
+	Name     string `json:"name"`
+	Module   string `json:"module"`
+	Schedule string `json:"schedule"`
+	Timezone string `json:"timezone"`
+	CatchUp  string `json:"catch_up"`
+}
+
+func (q *Queries) SaveScheduledJob(ctx context.Context, arg SaveScheduledJobParams) (ScheduledJob, error) {
+	row := q.db.QueryRowContext(ctx, saveScheduledJob,
+		arg.Name,
+		arg.Module,
+		arg.Schedule,
+		arg.Timezone,
+		arg.CatchUp,
+	)
+	var i ScheduledJob
+	err := row.Scan(
+		&i.Name,
+		&i.Module,
+		&i.Schedule,
+		&i.Timezone,
+		&i.CatchUp,
+		&i.LastRunAt,
+		&i.LastStatus,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const saveWatchMark = `-- name: SaveWatchMark :exec
+insert into watch_mark (guild_id, project_id, last_id)
+  values (?, ?, ?)
+on conflict (guild_id)
+  do update set
+    project_id = excluded.project_id, last_id = excluded.last_id, updated_at = current_timestamp
+`
+
+type SaveWatchMarkParams struct {
+	GuildID   string `json:"guild_id"`
+	ProjectID int64  `json:"project_id"`
+	LastID    int64  `json:"last_id"`
+}
+
+func (q *Queries) SaveWatchMark(ctx context.Context, arg SaveWatchMarkParams) error {
+	_, err := q.db.ExecContext(ctx, saveWatchMark, arg.GuildID, arg.ProjectID, arg.LastID)
+	return err
+}
+
+const saveWatchNotifiedAt = `-- name: SaveWatchNotifiedAt :exec
+insert into watch_settings (discord_user_id, last_notified_at)
+  values (?, ?)
+on conflict (discord_user_id)
+  do update set
+    last_notified_at = excluded.last_notified_at, updated_at = current_timestamp
+`
+
+type SaveWatchNotifiedAtParams struct {
+	DiscordUserID  string       `json:"discord_user_id"`
+	LastNotifiedAt sql.NullTime `json:"last_notified_at"`
+}
+
+func (q *Queries) SaveWatchNotifiedAt(ctx context.Context, arg SaveWatchNotifiedAtParams) error {
+	_, err := q.db.ExecContext(ctx, saveWatchNotifiedAt, arg.DiscordUserID, arg.LastNotifiedAt)
+	return err
+}
+
+const saveWatchSettings = `-- name: SaveWatchSettings :exec
+insert into watch_settings (discord_user_id, quiet_hours, timezone)
+  values (?, ?, ?)
+on conflict (discord_user_id)
+  do update set
+    quiet_hours = excluded.quiet_hours, timezone = excluded.timezone, updated_at = current_timestamp
+`
+
+type SaveWatchSettingsParams struct {
+	DiscordUserID string `json:"discord_user_id"`
+	QuietHours    string `json:"quiet_hours"`
+	Timezone      string `json:"timezone"`
+}
+
+func (q *Queries) SaveWatchSettings(ctx context.Context, arg SaveWatchSettingsParams) error {
+	_, err := q.db.ExecContext(ctx, saveWatchSettings, arg.DiscordUserID, arg.QuietHours, arg.Timezone)
+	return err
+}
+
+const takeCooldown = `-- name: TakeCooldown :execrows
+insert into cooldown (key, expires_at, created_at)
+  values (?, ?, ?)
+on conflict (key)
+  do update set
+    expires_at = excluded.expires_at, updated_at = current_timestamp
+  where
+    cooldown.expires_at <= excluded.created_at
+`
+
+type TakeCooldownParams struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// created_at is the current time, an existing cooldown is only replaced once
+// it has expired
+func (q *Queries) TakeCooldown(ctx context.Context, arg TakeCooldownParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, takeCooldown, arg.Key, arg.ExpiresAt, arg.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateModuleConfiguration = `-- name: UpdateModuleConfiguration :one
 update
   module_configuration
@@ -267,3 +2056,122 @@ func (q *Queries) UpdateModuleConfiguration(ctx context.Context, arg UpdateModul
 	err := row.Scan(&i.Module, &i.Key, &i.Data)
 	return i, err
 }
+
+const updateOutboxMessageAttempt = `-- name: UpdateOutboxMessageAttempt :exec
+update
+  outbox_message
+set
+  status = ?,
+  attempts = ?,
+  next_attempt_at = ?,
+  last_error = ?,
+  message_id = ?,
+  dedupe_key = ?,
+  updated_at = ?
+where
+  id = ?
+`
+
+type UpdateOutboxMessageAttemptParams struct {
+	Status        string    `json:"status"`
+	Attempts      int64     `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	LastError     string    `json:"last_error"`
+	MessageID     string    `json:"message_id"`
+	DedupeKey     string    `json:"dedupe_key"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	ID            int64     `json:"id"`
+}
+
+func (q *Queries) UpdateOutboxMessageAttempt(ctx context.Context, arg UpdateOutboxMessageAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, updateOutboxMessageAttempt,
+		arg.Status,
+		arg.Attempts,
+		arg.NextAttemptAt,
+		arg.LastError,
+		arg.MessageID,
+		arg.DedupeKey,
+		arg.UpdatedAt,
+		arg.ID,
+	)
+	return err
+}
+
+const updateScheduledJobLastRun = `-- name: UpdateScheduledJobLastRun :exec
+update
+  scheduled_job
+set
+  last_run_at = ?,
+  last_status = ?,
+  last_error = ?,
+  updated_at = current_timestamp
+where
+  name = ?
+`
+
+type UpdateScheduledJobLastRunParams struct {
+	LastRunAt  sql.NullTime `json:"last_run_at"`
+	LastStatus string       `json:"last_status"`
+	LastError  string       `json:"last_error"`
+	Name       string       `json:"name"`
+}
+
+func (q *Queries) UpdateScheduledJobLastRun(ctx context.Context, arg UpdateScheduledJobLastRunParams) error {
+	_, err := q.db.ExecContext(ctx, updateScheduledJobLastRun,
+		arg.LastRunAt,
+		arg.LastStatus,
+		arg.LastError,
+		arg.Name,
+	)
+	return err
+}
+
+const upsertObservation = `-- name: UpsertObservation :exec
+insert into observation (id, project_id, user_id, user_login, taxon_id,
+  iconic_taxon, taxon_ancestry, place_ids, quality_grade, faves_count,
+  photo_count, observed_on, data)
+  values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+on conflict (project_id, id)
+  do update set
+    user_id = excluded.user_id, user_login = excluded.user_login, taxon_id =
+      excluded.taxon_id, iconic_taxon = excluded.iconic_taxon, taxon_ancestry =
+      excluded.taxon_ancestry, place_ids = excluded.place_ids, quality_grade =
+      excluded.quality_grade, faves_count = excluded.faves_count, photo_count =
+      excluded.photo_count, observed_on = excluded.observed_on, data =
+      excluded.data, updated_at = current_timestamp
+`
+
+type UpsertObservationParams struct {
+	ID            int64       `json:"id"`
+	ProjectID     int64       `json:"project_id"`
+	UserID        int64       `json:"user_id"`
+	UserLogin     string      `json:"user_login"`
+	TaxonID       int64       `json:"taxon_id"`
+	IconicTaxon   string      `json:"iconic_taxon"`
+	TaxonAncestry string      `json:"taxon_ancestry"`
+	PlaceIds      string      `json:"place_ids"`
+	QualityGrade  string      `json:"quality_grade"`
+	FavesCount    int64       `json:"faves_count"`
+	PhotoCount    int64       `json:"photo_count"`
+	ObservedOn    string      `json:"observed_on"`
+	Data          interface{} `json:"data"`
+}
+
+func (q *Queries) UpsertObservation(ctx context.Context, arg UpsertObservationParams) error {
+	_, err := q.db.ExecContext(ctx, upsertObservation,
+		arg.ID,
+		arg.ProjectID,
+		arg.UserID,
+		arg.UserLogin,
+		arg.TaxonID,
+		arg.IconicTaxon,
+		arg.TaxonAncestry,
+		arg.PlaceIds,
+		arg.QualityGrade,
+		arg.FavesCount,
+		arg.PhotoCount,
+		arg.ObservedOn,
+		arg.Data,
+	)
+	return err
+}